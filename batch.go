@@ -0,0 +1,179 @@
+package pchecker
+
+import (
+	"context"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// BatchOptions configures CensorBatch and CensorPipeline
+type BatchOptions struct {
+	Workers     int             // number of goroutines, runtime.GOMAXPROCS by default
+	Replacement ReplacementFunc // nil uses the configured replacement of the detector
+}
+
+func (o BatchOptions) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// CensorBatch censors the inputs across several goroutines and returns the results in the
+// order of the inputs. The token buffers come from the shared pool of the detector, so the
+// workers reuse released buffers instead of allocating new ones. If ctx is cancelled the inputs not censored yet are left empty and the error
+// of the context is returned
+func (pd *ProfanityDetector) CensorBatch(ctx context.Context, inputs []string, opts BatchOptions) ([]string, error) {
+	f := pd.replacementOr(opts.Replacement)
+	results := make([]string, len(inputs))
+	var next atomic.Int64
+	var aborted atomic.Bool
+	var wg sync.WaitGroup
+	for range min(opts.workers(), len(inputs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(inputs) {
+					return
+				}
+				var err error
+				if err = ctx.Err(); err == nil {
+					results[i], err = pd.censor(inputs[i], f, &scanLimits{ctx: ctx})
+				}
+				if err != nil {
+					aborted.Store(true)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if aborted.Load() {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// CensorPipeline censors a stream of inputs across several goroutines and yields the results
+// in the order of the inputs. At most two inputs per worker are in flight, so slow consumers
+// slow down the reading of the inputs. If ctx is cancelled the iteration ends by yielding
+// the error of the context. The inputs are read by another goroutine which stops as soon as
+// inputs yields or returns. CensorPipeline waits for it when ctx is cancelled, so sources
+// blocking while they are idle must return once ctx is cancelled. When the caller stops the
+// iteration early it returns at once and the goroutine stops at the next value of inputs
+func (pd *ProfanityDetector) CensorPipeline(ctx context.Context, inputs iter.Seq[string], opts BatchOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		type job struct {
+			seq  int
+			text string
+		}
+		f := pd.replacementOr(opts.Replacement)
+		workers := opts.workers()
+		jobs := make(chan job)
+		results := make(chan job, workers)
+		window := make(chan struct{}, 2*workers)
+		read := make(chan struct{})
+		var aborted atomic.Bool
+		go func() {
+			defer close(read)
+			defer close(jobs)
+			seq := 0
+			for text := range inputs {
+				select {
+				case window <- struct{}{}:
+				case <-ctx.Done():
+					aborted.Store(true)
+					return
+				}
+				select {
+				case jobs <- job{seq: seq, text: text}:
+					seq++
+				case <-ctx.Done():
+					aborted.Store(true)
+					return
+				}
+			}
+		}()
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					var j job
+					select {
+					case next, ok := <-jobs:
+						if !ok {
+							return
+						}
+						j = next
+					case <-ctx.Done():
+						aborted.Store(true)
+						return
+					}
+					var err error
+					if j.text, err = pd.censor(j.text, f, &scanLimits{ctx: ctx}); err != nil {
+						aborted.Store(true)
+						return
+					}
+					select {
+					case results <- j:
+					case <-ctx.Done():
+						aborted.Store(true)
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+		// the goroutines must not outlive a cancelled iteration, the reader may still be calling
+		// inputs. A caller stopping early is not waited for, an idle source never sees it
+		stopped := false
+		defer func() {
+			cancel()
+			wg.Wait()
+			if !stopped {
+				<-read
+			}
+		}()
+		pending := make(map[int]string, 2*workers)
+		next := 0
+		for {
+			select {
+			case r, ok := <-results:
+				if !ok {
+					if aborted.Load() {
+						yield("", ctx.Err())
+					}
+					return
+				}
+				pending[r.seq] = r.text
+				for text, ok := pending[next]; ok; text, ok = pending[next] {
+					delete(pending, next)
+					next++
+					<-window
+					if !yield(text, nil) {
+						stopped = true
+						return
+					}
+				}
+			case <-ctx.Done():
+				yield("", ctx.Err())
+				return
+			}
+		}
+	}
+}
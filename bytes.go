@@ -0,0 +1,47 @@
+package pchecker
+
+import (
+	"unsafe"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// AppendCensored appends src with its censored tokens replaced by f to dst and returns the
+// extended buffer, a nil f uses the configured replacement. src is decoded in place and must
+// neither overlap dst nor be modified during the call. An invalid UTF-8 byte is one U+FFFD rune
+// of its token: outside censored tokens it is copied unchanged, inside it is replaced like any
+// other rune. Without any other allocation by f, nothing is allocated once dst is large enough
+func (pd *ProfanityDetector) AppendCensored(dst, src []byte, f ReplacementFunc) []byte {
+	if len(src) == 0 {
+		return dst
+	}
+	mask := f == nil && pd.replacement == nil
+	f = pd.replacementOr(f)
+	// the scan keeps no reference to its input, so src is read without copying it
+	input := unsafe.String(unsafe.SliceData(src), len(src))
+	last := 0
+	pd.scan(input, func(tb *tokenBuffer) {
+		if !tb.censored {
+			return
+		}
+		dst = append(dst, src[last:tb.start]...)
+		if mask {
+			for range tb.buff {
+				dst = append(dst, '*')
+			}
+		} else {
+			dst = append(dst, f(tb.buff)...)
+		}
+		last = tb.end
+	})
+	return append(dst, src[last:]...)
+}
+
+// CensorBytes writes src with its censored tokens replaced by f into dst, reusing its capacity,
+// and returns the result. It is AppendCensored(dst[:0], src, f)
+func (pd *ProfanityDetector) CensorBytes(dst, src []byte, f ReplacementFunc) []byte {
+	return pd.AppendCensored(dst[:0], src, f)
+}
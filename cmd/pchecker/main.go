@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/papajuan/pchecker"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+const usage = `usage: pchecker <command> [flags]
+
+commands:
+  lint   report dictionary entries that never fire, unused false positives,
+         duplicates and shadowed entries
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "lint":
+		err = lint(os.Args[2:], os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err == errIssues {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pchecker:", err)
+		os.Exit(2)
+	}
+}
+
+var errIssues = errors.New("issues found")
+
+func lint(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	policy := fs.String("policy", "default", "name of the policy to lint")
+	policies := fs.String("policies", "", "JSON file with policies to load first")
+	profanities := fs.String("profanities", "", "file with additional profanities, one per line")
+	falsePositives := fs.String("false-positives", "", "file with additional false positives, one per line")
+	falseNegatives := fs.String("false-negatives", "", "file with additional false negatives, one per line")
+	fs.Parse(args)
+
+	if *policies != "" {
+		f, err := os.Open(*policies)
+		if err != nil {
+			return err
+		}
+		err = pchecker.LoadPolicies(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	p, ok := pchecker.LookupPolicy(*policy)
+	if !ok {
+		return fmt.Errorf("unknown policy %q", *policy)
+	}
+	for _, list := range []struct {
+		file string
+		dict *pchecker.Dictionary
+	}{
+		{*profanities, &p.Profanities},
+		{*falsePositives, &p.FalsePositives},
+		{*falseNegatives, &p.FalseNegatives},
+	} {
+		entries, err := readEntries(list.file)
+		if err != nil {
+			return err
+		}
+		list.dict.Add = append(list.dict.Add[:len(list.dict.Add):len(list.dict.Add)], entries...)
+	}
+	issues, err := pchecker.Lint(p)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(out, issue)
+	}
+	if len(issues) > 0 {
+		return errIssues
+	}
+	return nil
+}
+
+// readEntries reads the non-empty lines of a file, lines starting with '#' are comments
+func readEntries(name string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			entries = append(entries, line)
+		}
+	}
+	return entries, sc.Err()
+}
@@ -0,0 +1,175 @@
+package pchecker
+
+import "strings"
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// ContextRule suppresses matches of its entries when one of the context words appears
+// within Window tokens before or after the matched token. A context word matches every
+// token starting with it, so "cancer" also matches "cancers". A rule without context
+// words suppresses its entries everywhere
+type ContextRule struct {
+	Entries []string // matched entries as reported by Explain, e.g. "breast"
+	Context []string // lower case neighbouring words
+	Window  int      // number of tokens searched on each side
+}
+
+// DefaultContextRules keep anatomical and medical terms readable in health-care text
+var DefaultContextRules = []ContextRule{
+	{
+		Entries: []string{"breast", "nipple"},
+		Context: []string{"cancer", "tumor", "tumour", "exam", "feeding", "feed", "milk", "pump", "surgery", "biopsy", "lump", "screening", "mammogra", "implant", "tissue", "reconstruct"},
+		Window:  3,
+	},
+	{
+		Entries: []string{"anal", "anus", "rectum"},
+		Context: []string{"fissure", "fistula", "cancer", "canal", "gland", "abscess", "sphincter", "exam", "bleeding", "itch", "pain", "surgery"},
+		Window:  2,
+	},
+	{
+		Entries: []string{"penis", "vagina", "scrotum", "clitoris"},
+		Context: []string{"cancer", "infection", "exam", "pain", "surgery", "anatomy", "discharge", "doctor", "urolog", "gynecolog", "gynaecolog"},
+		Window:  4,
+	},
+	{
+		Entries: []string{"abortion", "babies"},
+		Context: []string{"clinic", "spontaneous", "medical", "pregnan", "procedure", "doctor", "hospital", "premature", "newborn", "birth", "nurse", "care"},
+		Window:  4,
+	},
+	{
+		Entries: []string{"sex"},
+		Context: []string{"education", "hormone", "chromosome", "differences", "determination", "assigned", "ratio", "cell"},
+		Window:  2,
+	},
+}
+
+// ClinicalEntries are never censored by the clinical profile
+var ClinicalEntries = []string{"abortion", "anal", "anus", "babies", "breast", "clitoris", "naked", "nipple", "nude", "penis", "rectum", "scrotum", "sex", "vagina"}
+
+// contextRules indexes the context rules by entry
+type contextRules struct {
+	rules  map[string][]ContextRule
+	window int // largest window of all the rules
+}
+
+// WithContextRules adds rules suppressing matches depending on the neighbouring words
+func (pd *ProfanityDetector) WithContextRules(rules ...ContextRule) *ProfanityDetector {
+	if pd.contextRules == nil {
+		pd.contextRules = &contextRules{rules: make(map[string][]ContextRule)}
+	}
+	for _, rule := range rules {
+		for _, entry := range rule.Entries {
+			entry = strings.ToLower(entry)
+			pd.contextRules.rules[entry] = append(pd.contextRules.rules[entry], rule)
+		}
+		pd.contextRules.window = max(pd.contextRules.window, rule.Window)
+	}
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultContextRules() *ProfanityDetector {
+	return pd.WithContextRules(DefaultContextRules...)
+}
+
+// WithClinicalProfile stops censoring of ClinicalEntries, for text where anatomy is the subject
+func (pd *ProfanityDetector) WithClinicalProfile() *ProfanityDetector {
+	return pd.WithContextRules(ContextRule{Entries: ClinicalEntries})
+}
+
+// contextMatcher sits between the scan and its visitor like phraseMatcher. It holds back
+// censored tokens with context rules until enough following tokens are known to judge them
+type contextMatcher struct {
+	pd      *ProfanityDetector
+	history []string // words of the latest released tokens
+	pending []*tokenBuffer
+	words   []string // words of the pending tokens
+}
+
+func (cm *contextMatcher) visit(tb *tokenBuffer, next func(tb *tokenBuffer)) {
+	held := newTokenBuffer()
+	held.copyFrom(tb)
+	cm.pending = append(cm.pending, held)
+	cm.words = append(cm.words, string(tb.norm))
+	cm.release(false, next)
+}
+
+// release passes on the pending tokens which can be judged, or all of them if final is set
+func (cm *contextMatcher) release(final bool, next func(tb *tokenBuffer)) {
+	window := cm.pd.contextRules.window
+	for len(cm.pending) > 0 {
+		tb := cm.pending[0]
+		if tb.censored {
+			if !final && len(cm.pending) <= window && cm.hasRules(tb) {
+				return
+			}
+			tb.censored = !cm.suppressed(tb)
+		}
+		next(tb)
+		tb.close()
+		cm.history = append(cm.history, cm.words[0])
+		if len(cm.history) > window {
+			cm.history = cm.history[:copy(cm.history, cm.history[1:])]
+		}
+		cm.pending = cm.pending[:copy(cm.pending, cm.pending[1:])]
+		cm.words = cm.words[:copy(cm.words, cm.words[1:])]
+	}
+}
+
+func (cm *contextMatcher) hasRules(tb *tokenBuffer) bool {
+	for _, m := range tb.matches {
+		if len(cm.pd.contextRules.rules[cm.pd.match(tb, m).Entry]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressed reports whether every match censoring the first pending token is suppressed by a rule
+func (cm *contextMatcher) suppressed(tb *tokenBuffer) bool {
+	if len(tb.falseNegatives) > 0 {
+		return false
+	}
+	for _, m := range tb.matches {
+		if m.coveredBy(tb.falsePositives) {
+			continue
+		}
+		if !cm.applies(cm.pd.contextRules.rules[cm.pd.match(tb, m).Entry]) {
+			return false
+		}
+	}
+	return true
+}
+
+// applies reports whether any of the rules applies to the first pending token
+func (cm *contextMatcher) applies(rules []ContextRule) bool {
+	for _, rule := range rules {
+		if len(rule.Context) == 0 {
+			return true
+		}
+		before := cm.history[max(0, len(cm.history)-rule.Window):]
+		after := cm.words[1:min(len(cm.words), rule.Window+1)]
+		if containsContext(before, rule.Context) || containsContext(after, rule.Context) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsContext(words, context []string) bool {
+	for _, w := range words {
+		for _, c := range context {
+			if strings.HasPrefix(w, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// close passes the remaining pending tokens on
+func (cm *contextMatcher) close(next func(tb *tokenBuffer)) {
+	cm.release(true, next)
+}
@@ -0,0 +1,206 @@
+package pchecker
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// EntityKind is a kind of text entity that is recognized before tokenization
+type EntityKind uint8
+
+const (
+	EntityURL     EntityKind = iota // http://example.com/path, www.example.com
+	EntityEmail                     // user@example.com
+	EntityMention                   // @user_name
+	EntityHashtag                   // #hashtag
+	EntityCode                      // `code span`
+)
+
+// EntityPolicy defines how a recognized entity is censored
+type EntityPolicy uint8
+
+const (
+	// EntitySkip leaves the entity untouched
+	EntitySkip EntityPolicy = iota
+	// EntityCensor replaces the whole entity if any profanity is found inside it
+	EntityCensor
+	// EntityScan censors the words of the entity body (without '@', '#' or backticks) separately
+	EntityScan
+)
+
+// DefaultEntityPolicies censor whole addresses containing a profanity, and only the offending words
+// of mentions, hashtags and code
+var DefaultEntityPolicies = map[EntityKind]EntityPolicy{
+	EntityURL:     EntityCensor,
+	EntityEmail:   EntityCensor,
+	EntityMention: EntityScan,
+	EntityHashtag: EntityScan,
+	EntityCode:    EntityScan,
+}
+
+// WithEntityPolicy enables recognition of the entity kind with the given policy.
+// Entities are not recognized unless a policy is set
+func (pd *ProfanityDetector) WithEntityPolicy(kind EntityKind, policy EntityPolicy) *ProfanityDetector {
+	if pd.entityPolicies == nil {
+		pd.entityPolicies = make(map[EntityKind]EntityPolicy, len(DefaultEntityPolicies))
+	}
+	pd.entityPolicies[kind] = policy
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultEntityPolicies() *ProfanityDetector {
+	for kind, policy := range DefaultEntityPolicies {
+		pd.WithEntityPolicy(kind, policy)
+	}
+	return pd
+}
+
+// scanEntity applies the entity policy if a configured entity starts at input[i].
+// It returns the offset just after the entity, or i if there is none
+func (pd *ProfanityDetector) scanEntity(input string, i, base int, lim *scanLimits, visit func(tb *tokenBuffer)) int {
+	kind, bodyStart, bodyEnd, end := findEntity(input, i)
+	if end <= i {
+		return i
+	}
+	policy, ok := pd.entityPolicies[kind]
+	if !ok {
+		return i
+	}
+	switch policy {
+	case EntityCensor:
+		pd.censorEntity(input[i:end], base+i, visit)
+	case EntityScan:
+		var mode scanMode
+		if kind == EntityHashtag {
+			mode = scanWordBreak
+		}
+		pd.scanTokens(input[bodyStart:bodyEnd], base+bodyStart, mode, lim, visit)
+	}
+	return end
+}
+
+// censorEntity reports the entity located at the byte offset base as a single token
+// which is censored if any of its words is
+func (pd *ProfanityDetector) censorEntity(entity string, base int, visit func(tb *tokenBuffer)) {
+	et := newTokenBuffer()
+	defer et.close()
+	for i, r := range entity {
+		et.push(r, pd.normalize(r), base+i)
+	}
+	et.offsets = append(et.offsets, base+len(entity))
+	et.start, et.end = base, base+len(entity)
+	pd.scanTokens(entity, base, 0, nil, func(tb *tokenBuffer) {
+		if !tb.censored {
+			return
+		}
+		et.censored = true
+		first, _ := slices.BinarySearch(et.offsets, tb.start)
+		for _, m := range tb.matches {
+			et.matches = append(et.matches, runeSpan{start: first + m.start, end: first + m.end})
+		}
+	})
+	visit(et)
+}
+
+// findEntity recognizes an entity starting at input[i]. It returns the entity kind,
+// the byte span of its body and the offset just after it, which is i if there is no entity
+func findEntity(input string, i int) (kind EntityKind, bodyStart, bodyEnd, end int) {
+	rest := input[i:]
+	switch rest[0] {
+	case '@':
+		n := 1
+		for n < len(rest) && (isASCIIAlnum(rest[n]) || rest[n] == '_') {
+			n++
+		}
+		if n > 1 {
+			return EntityMention, i + 1, i + n, i + n
+		}
+	case '#':
+		n, letter := 1, false
+		for n < len(rest) {
+			r, size := utf8.DecodeRuneInString(rest[n:])
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				break
+			}
+			letter = letter || unicode.IsLetter(r)
+			n += size
+		}
+		if letter {
+			return EntityHashtag, i + 1, i + n, i + n
+		}
+	case '`':
+		if closeAt := strings.IndexByte(rest[1:], '`'); closeAt > 0 {
+			return EntityCode, i + 1, i + 1 + closeAt, i + closeAt + 2
+		}
+	default:
+		if n := urlLen(rest); n > 0 {
+			return EntityURL, i, i + n, i + n
+		}
+		if n := emailLen(rest); n > 0 {
+			return EntityEmail, i, i + n, i + n
+		}
+	}
+	return 0, i, i, i
+}
+
+// urlLen returns the length of the URL s starts with, or 0
+func urlLen(s string) int {
+	n := 0
+	for n < len(s) && (isASCIIAlnum(s[n]) || s[n] == '+' || s[n] == '.' || s[n] == '-') {
+		n++
+	}
+	switch {
+	case n > 1 && isASCIILetter(s[0]) && strings.HasPrefix(s[n:], "://"):
+	case n > 4 && strings.EqualFold(s[:4], "www."):
+	default:
+		return 0
+	}
+	n = strings.IndexAny(s, " \t\r\n<>\"")
+	if n < 0 {
+		n = len(s)
+	}
+	// trailing punctuation most likely belongs to the sentence, not to the URL
+	for n > 0 {
+		c := s[n-1]
+		if c == ')' && strings.Count(s[:n], "(") >= strings.Count(s[:n], ")") {
+			break
+		}
+		if !strings.ContainsRune(".,;:!?')", rune(c)) {
+			break
+		}
+		n--
+	}
+	return n
+}
+
+// emailLen returns the length of the email address s starts with, or 0
+func emailLen(s string) int {
+	n := 0
+	for n < len(s) && (isASCIIAlnum(s[n]) || strings.IndexByte("._%+-", s[n]) >= 0) {
+		n++
+	}
+	if n == 0 || n == len(s) || s[n] != '@' {
+		return 0
+	}
+	at := n
+	n++
+	dot := false
+	for n < len(s) && (isASCIIAlnum(s[n]) || s[n] == '.' || s[n] == '-') {
+		dot = dot || s[n] == '.'
+		n++
+	}
+	for n > at+1 && s[n-1] == '.' {
+		n--
+	}
+	if !dot || n == at+1 || !strings.Contains(s[at+1:n], ".") {
+		return 0
+	}
+	return n
+}
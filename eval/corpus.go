@@ -0,0 +1,141 @@
+package eval
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/papajuan/pchecker"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Sample is a labeled text of a corpus
+type Sample struct {
+	ID        string            `json:"id"`
+	Text      string            `json:"text"`
+	Locale    string            `json:"locale,omitempty"`
+	Offensive bool              `json:"offensive"`          // implied by Spans
+	Category  pchecker.Category `json:"category,omitempty"` // category of an offensive sample without spans
+	Spans     []Span            `json:"spans,omitempty"`    // byte spans expected to be censored
+}
+
+// Span is a byte range of a sample text expected to be censored
+type Span struct {
+	Start    int               `json:"start"`
+	End      int               `json:"end"`
+	Category pchecker.Category `json:"category,omitempty"`
+}
+
+// ReadJSONL reads one JSON sample per line, empty lines are skipped
+func ReadJSONL(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("eval: line %d: %w", line, err)
+		}
+		if err := s.init(line); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+	return samples, sc.Err()
+}
+
+// ReadCSV reads samples from a CSV with a header row. The text column is required, the optional
+// columns are id, locale, offensive (true/false or 1/0), category and spans, which lists
+// "start-end" or "start-end:category" ranges separated by ';'
+func ReadCSV(r io.Reader) ([]Sample, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("eval: reading the header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["text"]; !ok {
+		return nil, errors.New("eval: missing text column")
+	}
+	var samples []Sample
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("eval: line %d: %w", line, err)
+		}
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		s := Sample{
+			ID:       get("id"),
+			Text:     record[columns["text"]],
+			Locale:   get("locale"),
+			Category: pchecker.Category(get("category")),
+		}
+		if v := get("offensive"); v != "" {
+			if s.Offensive, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("eval: line %d: invalid offensive value %q", line, v)
+			}
+		}
+		if s.Spans, err = parseSpans(get("spans")); err != nil {
+			return nil, fmt.Errorf("eval: line %d: %w", line, err)
+		}
+		if err := s.init(line); err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+	}
+}
+
+func parseSpans(s string) ([]Span, error) {
+	var spans []Span
+	for _, field := range strings.Split(s, ";") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		rng, category, _ := strings.Cut(field, ":")
+		from, to, ok := strings.Cut(rng, "-")
+		start, err1 := strconv.Atoi(from)
+		end, err2 := strconv.Atoi(to)
+		if !ok || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid span %q", field)
+		}
+		spans = append(spans, Span{Start: start, End: end, Category: pchecker.Category(category)})
+	}
+	return spans, nil
+}
+
+// init checks the sample read from the given line and fills in the implied fields
+func (s *Sample) init(line int) error {
+	if s.ID == "" {
+		s.ID = strconv.Itoa(line)
+	}
+	for _, sp := range s.Spans {
+		if sp.Start < 0 || sp.End <= sp.Start || sp.End > len(s.Text) {
+			return fmt.Errorf("eval: line %d: span [%d:%d] out of the text", line, sp.Start, sp.End)
+		}
+	}
+	s.Offensive = s.Offensive || len(s.Spans) > 0
+	return nil
+}
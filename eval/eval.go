@@ -0,0 +1,274 @@
+// Package eval measures a profanity detector against labeled corpora
+package eval
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/papajuan/pchecker"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Metrics counts the outcomes of an evaluation
+type Metrics struct {
+	TruePositives  int
+	FalsePositives int
+	FalseNegatives int
+}
+
+func (m Metrics) Precision() float64 {
+	return ratio(m.TruePositives, m.TruePositives+m.FalsePositives)
+}
+
+func (m Metrics) Recall() float64 {
+	return ratio(m.TruePositives, m.TruePositives+m.FalseNegatives)
+}
+
+// F1 is the harmonic mean of precision and recall
+func (m Metrics) F1() float64 {
+	p, r := m.Precision(), m.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// ErrorKind tells whether the detector censored too much or too little
+type ErrorKind string
+
+const (
+	FalsePositive ErrorKind = "false-positive"
+	FalseNegative ErrorKind = "false-negative"
+)
+
+// Error is a wrong decision of the detector. Samples labeled with spans are judged span by span,
+// the others as a whole: a missed sample is one error covering the text, a wrongly censored
+// sample counts as one false positive but lists an error per violation
+type Error struct {
+	Kind     ErrorKind
+	SampleID string
+	Locale   string
+	Category pchecker.Category
+	Start    int
+	End      int
+	Text     string // censored or expected to be censored
+}
+
+func (e Error) String() string {
+	return fmt.Sprintf("%s %s [%d:%d] %q", e.Kind, e.SampleID, e.Start, e.End, e.Text)
+}
+
+// Report is the result of an evaluation
+type Report struct {
+	Overall    Metrics
+	ByCategory map[pchecker.Category]Metrics
+	ByLocale   map[string]Metrics
+	Errors     []Error // ordered by sample and position
+}
+
+// Run evaluates the detector on the samples
+func Run(pd *pchecker.ProfanityDetector, samples []Sample) Report {
+	r := Report{
+		ByCategory: make(map[pchecker.Category]Metrics),
+		ByLocale:   make(map[string]Metrics),
+	}
+	for _, s := range samples {
+		var violations []pchecker.Violation
+		if pe, ok := pd.Validate(s.Text).(*pchecker.ProfanityError); ok {
+			violations = pe.Violations
+		}
+		first := len(r.Errors)
+		if len(s.Spans) > 0 {
+			r.judgeSpans(s, violations)
+		} else {
+			r.judgeSample(s, violations)
+		}
+		slices.SortStableFunc(r.Errors[first:], func(a, b Error) int {
+			return cmp.Compare(a.Start, b.Start)
+		})
+	}
+	return r
+}
+
+// judgeSpans matches every expected span with the first overlapping violation not matched yet,
+// so a violation counts for a single span
+func (r *Report) judgeSpans(s Sample, violations []pchecker.Violation) {
+	found := make([]bool, len(violations))
+	for _, sp := range s.Spans {
+		hit := false
+		for i, v := range violations {
+			if !found[i] && v.Start < sp.End && sp.Start < v.End {
+				hit, found[i] = true, true
+				break
+			}
+		}
+		if hit {
+			r.count(s.Locale, sp.Category, func(m *Metrics) { m.TruePositives++ })
+		} else {
+			r.fail(Error{Kind: FalseNegative, SampleID: s.ID, Locale: s.Locale, Category: sp.Category, Start: sp.Start, End: sp.End, Text: s.Text[sp.Start:sp.End]})
+		}
+	}
+	for i, v := range violations {
+		if !found[i] {
+			r.fail(Error{Kind: FalsePositive, SampleID: s.ID, Locale: s.Locale, Category: category(v), Start: v.Start, End: v.End, Text: v.Token})
+		}
+	}
+}
+
+// judgeSample compares the label of the sample with the presence of violations
+func (r *Report) judgeSample(s Sample, violations []pchecker.Violation) {
+	predicted := len(violations) > 0
+	c := s.Category
+	if c == "" && predicted {
+		c = category(violations[0])
+	}
+	switch {
+	case s.Offensive && predicted:
+		r.count(s.Locale, c, func(m *Metrics) { m.TruePositives++ })
+	case s.Offensive:
+		r.fail(Error{Kind: FalseNegative, SampleID: s.ID, Locale: s.Locale, Category: c, End: len(s.Text), Text: s.Text})
+	case predicted:
+		for _, v := range violations {
+			r.Errors = append(r.Errors, Error{Kind: FalsePositive, SampleID: s.ID, Locale: s.Locale, Category: category(v), Start: v.Start, End: v.End, Text: v.Token})
+		}
+		r.count(s.Locale, c, func(m *Metrics) { m.FalsePositives++ })
+	}
+}
+
+func category(v pchecker.Violation) pchecker.Category {
+	if len(v.Categories) == 0 {
+		return ""
+	}
+	return v.Categories[0]
+}
+
+func (r *Report) fail(e Error) {
+	r.Errors = append(r.Errors, e)
+	r.count(e.Locale, e.Category, func(m *Metrics) {
+		if e.Kind == FalsePositive {
+			m.FalsePositives++
+		} else {
+			m.FalseNegatives++
+		}
+	})
+}
+
+// count applies f to the overall metrics and to those of the locale and category if set
+func (r *Report) count(locale string, c pchecker.Category, f func(m *Metrics)) {
+	f(&r.Overall)
+	if c != "" {
+		m := r.ByCategory[c]
+		f(&m)
+		r.ByCategory[c] = m
+	}
+	if locale != "" {
+		m := r.ByLocale[locale]
+		f(&m)
+		r.ByLocale[locale] = m
+	}
+}
+
+// WriteTo writes the metrics and errors as text
+func (r Report) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	writeMetrics(cw, "overall", r.Overall)
+	for _, c := range slices.Sorted(maps.Keys(r.ByCategory)) {
+		writeMetrics(cw, "category "+string(c), r.ByCategory[c])
+	}
+	for _, l := range slices.Sorted(maps.Keys(r.ByLocale)) {
+		writeMetrics(cw, "locale "+l, r.ByLocale[l])
+	}
+	for _, e := range r.Errors {
+		fmt.Fprintln(cw, e)
+	}
+	return cw.n, cw.err
+}
+
+func writeMetrics(w io.Writer, name string, m Metrics) {
+	fmt.Fprintf(w, "%-24s precision %.3f recall %.3f f1 %.3f (tp %d, fp %d, fn %d)\n",
+		name, m.Precision(), m.Recall(), m.F1(), m.TruePositives, m.FalsePositives, m.FalseNegatives)
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+// Diff compares the reports of two detector configurations on the same samples
+type Diff struct {
+	Baseline  Report
+	Candidate Report
+	Fixed     []Error // errors of the baseline the candidate doesn't make
+	Regressed []Error // errors of the candidate the baseline didn't make
+}
+
+// Compare evaluates both detectors on the samples and diffs their errors
+func Compare(baseline, candidate *pchecker.ProfanityDetector, samples []Sample) Diff {
+	d := Diff{
+		Baseline:  Run(baseline, samples),
+		Candidate: Run(candidate, samples),
+	}
+	d.Fixed = subtract(d.Baseline.Errors, d.Candidate.Errors)
+	d.Regressed = subtract(d.Candidate.Errors, d.Baseline.Errors)
+	return d
+}
+
+// subtract returns the errors of a that are not in b
+func subtract(a, b []Error) []Error {
+	type key struct {
+		kind       ErrorKind
+		id         string
+		start, end int
+	}
+	seen := make(map[key]bool, len(b))
+	for _, e := range b {
+		seen[key{e.Kind, e.SampleID, e.Start, e.End}] = true
+	}
+	var result []Error
+	for _, e := range a {
+		if !seen[key{e.Kind, e.SampleID, e.Start, e.End}] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// WriteTo writes the change of the overall metrics followed by the fixed and regressed errors
+func (d Diff) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	b, c := d.Baseline.Overall, d.Candidate.Overall
+	fmt.Fprintf(cw, "precision %.3f -> %.3f, recall %.3f -> %.3f, f1 %.3f -> %.3f\n",
+		b.Precision(), c.Precision(), b.Recall(), c.Recall(), b.F1(), c.F1())
+	for _, e := range d.Fixed {
+		fmt.Fprintln(cw, "fixed", e)
+	}
+	for _, e := range d.Regressed {
+		fmt.Fprintln(cw, "regressed", e)
+	}
+	return cw.n, cw.err
+}
@@ -0,0 +1,126 @@
+package eval
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/papajuan/pchecker"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+const corpusJSONL = `{"id":"a","text":"what the fuck","locale":"en","spans":[{"start":9,"end":13,"category":"profanity"}]}
+{"id":"b","text":"a lovely day","locale":"en"}
+
+{"id":"c","text":"classic assassin","locale":"en"}
+{"id":"d","text":"du bist ein arschloch","locale":"de","offensive":true,"category":"insult"}
+{"id":"e","text":"nice tits and a crap day","locale":"en","spans":[{"start":5,"end":9,"category":"sexual"}]}
+`
+
+const corpusCSV = `id,text,locale,offensive,category,spans
+a,what the fuck,en,,,9-13:profanity
+b,a lovely day,en,false,,
+c,classic assassin,en,,,
+d,du bist ein arschloch,de,1,insult,
+e,nice tits and a crap day,en,,,5-9:sexual
+`
+
+func TestReadCorpus(t *testing.T) {
+	fromJSONL, err := ReadJSONL(strings.NewReader(corpusJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := ReadCSV(strings.NewReader(corpusCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fromJSONL) != 5 || !slices.EqualFunc(fromJSONL, fromCSV, func(a, b Sample) bool {
+		return a.ID == b.ID && a.Text == b.Text && a.Locale == b.Locale && a.Offensive == b.Offensive &&
+			a.Category == b.Category && slices.Equal(a.Spans, b.Spans)
+	}) {
+		t.Errorf("expected the same samples, got\n%+v\n%+v", fromJSONL, fromCSV)
+	}
+	if _, err := ReadJSONL(strings.NewReader(`{"text":"abc","spans":[{"start":2,"end":9}]}`)); err == nil {
+		t.Error("expected an error for a span out of the text")
+	}
+	if _, err := ReadCSV(strings.NewReader("id,label\n1,0\n")); err == nil {
+		t.Error("expected an error for a missing text column")
+	}
+}
+
+func TestRun(t *testing.T) {
+	samples, err := ReadJSONL(strings.NewReader(corpusJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Run(pchecker.NewDefaultProfanityDetector(), samples)
+	if expected := (Metrics{TruePositives: 2, FalsePositives: 1, FalseNegatives: 1}); r.Overall != expected {
+		t.Errorf("expected %+v, got %+v", expected, r.Overall)
+	}
+	if m := r.ByLocale["de"]; m.FalseNegatives != 1 || m.Recall() != 0 {
+		t.Errorf("expected the german insult to be missed, got %+v", m)
+	}
+	if m := r.ByCategory[pchecker.CategorySexual]; m.TruePositives != 1 || m.F1() != 1 {
+		t.Errorf("expected the sexual span to be found, got %+v", m)
+	}
+	expected := []Error{
+		{Kind: FalseNegative, SampleID: "d", Locale: "de", Category: pchecker.CategoryInsult, Start: 0, End: 21, Text: "du bist ein arschloch"},
+		{Kind: FalsePositive, SampleID: "e", Locale: "en", Category: pchecker.CategoryProfanity, Start: 16, End: 20, Text: "crap"},
+	}
+	if !slices.Equal(r.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, r.Errors)
+	}
+	var out bytes.Buffer
+	if _, err := r.WriteTo(&out); err != nil || !strings.Contains(out.String(), "locale de") {
+		t.Errorf("unexpected report %q", out.String())
+	}
+}
+
+func TestRun_AdjacentSpans(t *testing.T) {
+	samples := []Sample{{ID: "a", Text: "fuckshit", Spans: []Span{
+		{Start: 0, End: 4, Category: pchecker.CategoryProfanity},
+		{Start: 4, End: 8, Category: pchecker.CategoryProfanity},
+	}}}
+	r := Run(pchecker.NewDefaultProfanityDetector(), samples)
+	if expected := (Metrics{TruePositives: 1, FalseNegatives: 1}); r.Overall != expected {
+		t.Errorf("expected a single violation to match a single span, got %+v", r.Overall)
+	}
+}
+
+func TestRun_LabeledSamples(t *testing.T) {
+	samples := []Sample{
+		{ID: "a", Text: "what the fuck", Offensive: true},
+		{ID: "b", Text: "shit, crap", Offensive: false},
+	}
+	r := Run(pchecker.NewDefaultProfanityDetector(), samples)
+	if expected := (Metrics{TruePositives: 1, FalsePositives: 1}); r.Overall != expected || r.Overall.Precision() != 0.5 {
+		t.Errorf("expected a wrongly censored sample to count once, got %+v", r.Overall)
+	}
+	if len(r.Errors) != 2 || r.Errors[0].Text != "shit" || r.Errors[1].Text != "crap" {
+		t.Errorf("expected both violations to be listed, got %v", r.Errors)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	samples, err := ReadJSONL(strings.NewReader(corpusJSONL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidate := pchecker.NewDefaultProfanityDetector().
+		WithProfanities(map[string]bool{"fuck": true, "tits": true, "arschloch": true, "lovely": true})
+	d := Compare(pchecker.NewDefaultProfanityDetector(), candidate, samples)
+	if len(d.Fixed) != 2 || d.Fixed[0].SampleID != "d" || d.Fixed[1].SampleID != "e" {
+		t.Errorf("expected the errors of d and e to be fixed, got %v", d.Fixed)
+	}
+	if len(d.Regressed) != 1 || d.Regressed[0].SampleID != "b" || d.Regressed[0].Kind != FalsePositive {
+		t.Errorf("expected a false positive in b, got %v", d.Regressed)
+	}
+	if d.Candidate.Overall.Recall() != 1 {
+		t.Errorf("expected a full recall, got %+v", d.Candidate.Overall)
+	}
+}
@@ -0,0 +1,106 @@
+package pchecker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Match is a profanity found in the input
+type Match struct {
+	Entry    string // dictionary entry that matched
+	Start    int    // byte offset of the first matched rune in the input
+	End      int    // byte offset just after the last matched rune in the input
+	Distance int    // edit distance between the entry and the matched runes, 0 unless fuzzy matching found it
+	Phonetic bool   // the matched runes only sound like the entry
+	Severity float64
+	Category Category
+}
+
+// TokenExplanation describes why a token was or wasn't censored
+type TokenExplanation struct {
+	Token          string  // token as it appears in the input
+	Start          int     // byte offset of the token in the input
+	End            int     // byte offset just after the token in the input
+	Normalized     string  // token after character replacement and lower casing
+	Matches        []Match // every profanity found in the normalized token
+	FalsePositives []Match // false positives fully covering at least one of the matches
+	FalseNegatives []Match // false negatives forcing the token to be censored
+	Censored       bool
+}
+
+// Explain reports for every token of the input how Censor judges it
+func (pd *ProfanityDetector) Explain(input string) []TokenExplanation {
+	var result []TokenExplanation
+	pd.scan(input, func(tb *tokenBuffer) {
+		e := TokenExplanation{
+			Token:      input[tb.start:tb.end],
+			Start:      tb.start,
+			End:        tb.end,
+			Normalized: string(tb.norm),
+			Censored:   tb.censored,
+		}
+		for _, m := range tb.matches {
+			e.Matches = append(e.Matches, pd.match(tb, m))
+		}
+		for _, fp := range tb.falsePositives {
+			if slices.ContainsFunc(tb.matches, func(m runeSpan) bool { return m.coveredBy([]runeSpan{fp}) }) {
+				e.FalsePositives = append(e.FalsePositives, pd.match(tb, fp))
+			}
+		}
+		for _, fn := range tb.falseNegatives {
+			e.FalseNegatives = append(e.FalseNegatives, pd.match(tb, fn))
+		}
+		result = append(result, e)
+	})
+	return result
+}
+
+// match converts a match within the token into a Match of the input
+func (pd *ProfanityDetector) match(tb *tokenBuffer, m runeSpan) Match {
+	info := pd.info(tb, m)
+	return Match{
+		Entry:    info.Entry,
+		Start:    tb.offsets[m.start],
+		End:      tb.offsets[m.end],
+		Distance: m.distance,
+		Phonetic: m.phonetic,
+		Severity: info.Severity,
+		Category: info.Category,
+	}
+}
+
+func (e TokenExplanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%q [%d:%d] normalized %q", e.Token, e.Start, e.End, e.Normalized)
+	writeMatches(&sb, "matches", e.Matches)
+	writeMatches(&sb, "false positives", e.FalsePositives)
+	writeMatches(&sb, "false negatives", e.FalseNegatives)
+	if e.Censored {
+		sb.WriteString(": censored")
+	} else {
+		sb.WriteString(": kept")
+	}
+	return sb.String()
+}
+
+func writeMatches(sb *strings.Builder, title string, matches []Match) {
+	for i, m := range matches {
+		if i == 0 {
+			sb.WriteString(", ")
+			sb.WriteString(title)
+		}
+		fmt.Fprintf(sb, " %q [%d:%d]", m.Entry, m.Start, m.End)
+		if m.Distance > 0 {
+			fmt.Fprintf(sb, " ~%d", m.Distance)
+		}
+		if m.Phonetic {
+			sb.WriteString(" phonetic")
+		}
+	}
+}
@@ -3,6 +3,7 @@ package pchecker
 // DefaultFalsePositives is a list of words that may wrongly trigger the DefaultProfanities
 var DefaultFalsePositives = map[string]bool{
 	"analy":         true, // analysis, analytics
+	"ares":          true, // a transposed arse
 	"arsenal":       true,
 	"assassin":      true,
 	"assaying":      true, // was saying
@@ -20,6 +21,7 @@ var DefaultFalsePositives = map[string]bool{
 	"butter":        true, // butter, butterfly
 	"button":        true,
 	"canvass":       true,
+	"carp":          true, // a transposed crap
 	"circum":        true,
 	"clitheroe":     true,
 	"cockburn":      true,
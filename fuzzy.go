@@ -0,0 +1,251 @@
+package pchecker
+
+import (
+	"slices"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// FuzzyDistance returns the maximum edit distance allowed for a dictionary entry of the given length,
+// or FuzzyTransposition
+type FuzzyDistance func(length int) int
+
+// FuzzyTransposition only allows whole words with two adjacent runes of the entry swapped, like
+// "fukc" or "btich", it keeps short entries from matching common words one edit away
+const FuzzyTransposition = -1
+
+// DefaultFuzzyDistance allows no edits in entries shorter than 4 runes, a transposition in entries
+// shorter than 7 runes, which are one edit away from too many common words ("duck", "count", "regard"),
+// one edit in entries shorter than 10 runes and two edits in longer ones
+func DefaultFuzzyDistance(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 7:
+		return FuzzyTransposition
+	case length < 10:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fuzzyPinned is the number of leading runes of an entry that cannot be inserted, deleted or
+// replaced by an unrelated rune. The first one has to match exactly, the others may be swapped
+// with the next rune or replaced by a look-alike
+const fuzzyPinned = 2
+
+// maxFuzzyEntryLength is the entry length used to bound the search of FuzzyDistance
+const maxFuzzyEntryLength = 64
+
+// the costs of the edits in half edits
+const (
+	fuzzyEdit      = 2
+	fuzzyLookAlike = 1 // replacing a rune by a look-alike
+)
+
+// fuzzyClass returns the class of look-alike runes r belongs to, often used to evade filters
+func fuzzyClass(r rune) rune {
+	switch r {
+	case 'z':
+		return 's'
+	case 'k', 'q':
+		return 'c'
+	case 'y':
+		return 'i'
+	}
+	return r
+}
+
+// WithFuzzy enables matching of profanities with misspellings like "bastrad", "azzhole" or "fukc".
+// The Damerau-Levenshtein (optimal string alignment) distance between an entry and a part of a word
+// may be up to distance(len(entry)). Replacing a rune by a look-alike ('z' for 's', 'k' or 'q' for 'c',
+// 'y' for 'i') counts as half an edit. The first rune of the entry has to match exactly and the second
+// one may only be swapped or replaced by a look-alike
+func (pd *ProfanityDetector) WithFuzzy(distance FuzzyDistance) *ProfanityDetector {
+	pd.fuzzy = distance
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultFuzzy() *ProfanityDetector {
+	return pd.WithFuzzy(DefaultFuzzyDistance)
+}
+
+const fuzzyInf = 1 << 20
+
+// fuzzySearch walks the profanity trie computing the edit cost rows of every trie path
+// against the best matching substring of the token
+type fuzzySearch struct {
+	distance FuzzyDistance
+	maxCost  int
+	text     []rune
+	word     []rune  // trie path of the current row
+	cost     [][]int // cost[i][j] is the cost between word[:i] and the best substring ending at text[j]
+	start    [][]int // start[i][j] is the start of that substring
+	best     map[*node[rune]]fuzzyMatch
+}
+
+type fuzzyMatch struct {
+	span runeSpan
+	cost int
+}
+
+// fuzzyMatches appends the profanities found in the token with at least one edit to its matches
+func (pd *ProfanityDetector) fuzzyMatches(tb *tokenBuffer) {
+	if pd.profanities == nil {
+		return
+	}
+	s := fuzzySearch{
+		distance: pd.fuzzy,
+		maxCost:  fuzzyCost(pd.fuzzy(maxFuzzyEntryLength)),
+		text:     tb.norm,
+	}
+	if s.maxCost <= 0 {
+		return
+	}
+	s.visit(pd.profanities.root)
+	first := len(tb.matches)
+	for n, m := range s.best {
+		m.span.entry = n
+		tb.matches = append(tb.matches, m.span)
+	}
+	slices.SortFunc(tb.matches[first:], func(a, b runeSpan) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		if a.end != b.end {
+			return a.end - b.end
+		}
+		return strings.Compare(a.word, b.word)
+	})
+}
+
+// fuzzyCost returns the maximum cost of a distance returned by FuzzyDistance
+func fuzzyCost(distance int) int {
+	if distance == FuzzyTransposition {
+		return fuzzyEdit
+	}
+	return distance * fuzzyEdit
+}
+
+func (s *fuzzySearch) visit(n *node[rune]) {
+	depth := len(s.word)
+	if depth > 0 {
+		row := s.cost[depth]
+		if n.isEnd {
+			s.report(n, row)
+		}
+		// a transposition with the next rune continues from the previous row
+		if slices.Min(row) > s.maxCost && (depth == 1 || slices.Min(s.cost[depth-1])+fuzzyEdit > s.maxCost) {
+			return
+		}
+	}
+	for r, child := range n.children {
+		s.descend(child, r, false)
+	}
+	if n.wildcard != nil {
+		s.descend(n.wildcard, 0, true)
+	}
+}
+
+func (s *fuzzySearch) descend(child *node[rune], r rune, any bool) {
+	s.word = append(s.word, r)
+	s.computeRow(any)
+	s.visit(child)
+	s.word = s.word[:len(s.word)-1]
+}
+
+// computeRow computes the row of the last rune of the word, any marks a wildcard
+func (s *fuzzySearch) computeRow(any bool) {
+	i := len(s.word)
+	for len(s.cost) <= i {
+		s.cost = append(s.cost, make([]int, len(s.text)+1))
+		s.start = append(s.start, make([]int, len(s.text)+1))
+	}
+	cur, curStart := s.cost[i], s.start[i]
+	c := s.word[i-1]
+	substitution := func(j int) int {
+		switch r := s.text[j]; {
+		case any || r == c:
+			return 0
+		case fuzzyClass(r) == fuzzyClass(c):
+			return fuzzyLookAlike
+		default:
+			return fuzzyEdit
+		}
+	}
+	cur[0] = fuzzyInf
+	if i == 1 {
+		// the first rune of the entry has to match exactly
+		for j := 1; j <= len(s.text); j++ {
+			cur[j], curStart[j] = fuzzyInf, j-1
+			if any || s.text[j-1] == c {
+				cur[j] = 0
+			}
+		}
+		return
+	}
+	prev, prevStart := s.cost[i-1], s.start[i-1]
+	for j := 1; j <= len(s.text); j++ {
+		cost := substitution(j - 1)
+		if i <= fuzzyPinned && cost == fuzzyEdit {
+			cost = fuzzyInf
+		}
+		cur[j], curStart[j] = prev[j-1]+cost, prevStart[j-1] // substitution
+		if i > fuzzyPinned {
+			if d := prev[j] + fuzzyEdit; d < cur[j] { // deletion of the entry rune
+				cur[j], curStart[j] = d, prevStart[j]
+			}
+			if d := cur[j-1] + fuzzyEdit; d < cur[j] { // insertion of the text rune
+				cur[j], curStart[j] = d, curStart[j-1]
+			}
+		}
+		if i > 2 && j > 1 && s.text[j-2] == c && s.text[j-1] == s.word[i-2] { // transposition
+			if d := s.cost[i-2][j-2] + fuzzyEdit; d < cur[j] {
+				cur[j], curStart[j] = d, s.start[i-2][j-2]
+			}
+		}
+	}
+}
+
+// report remembers the best approximate match of the entry ending at n,
+// among matches at the same cost the one ending last wins
+func (s *fuzzySearch) report(n *node[rune], row []int) {
+	allowed := s.distance(len(s.word))
+	for j := 1; j < len(row); j++ {
+		d := row[j]
+		if d == 0 || d > fuzzyCost(allowed) {
+			continue
+		}
+		start := s.start[len(s.word)][j]
+		if allowed == FuzzyTransposition && !s.transposed(start, j) {
+			continue
+		}
+		if prev, ok := s.best[n]; ok && prev.cost < d {
+			continue
+		}
+		if s.best == nil {
+			s.best = make(map[*node[rune]]fuzzyMatch)
+		}
+		m := runeSpan{start: start, end: j, distance: (d + 1) / fuzzyEdit, word: string(s.word)}
+		s.best[n] = fuzzyMatch{span: m, cost: d}
+	}
+}
+
+// transposed reports whether text[start:end] is the whole token and the word with two adjacent runes swapped
+func (s *fuzzySearch) transposed(start, end int) bool {
+	if start != 0 || end != len(s.text) || end != len(s.word) {
+		return false
+	}
+	for i := range s.word {
+		if s.word[i] != s.text[i] {
+			return i+1 < end && s.word[i] == s.text[i+1] && s.word[i+1] == s.text[i] &&
+				slices.Equal(s.word[i+2:], s.text[i+2:])
+		}
+	}
+	return false
+}
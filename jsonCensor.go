@@ -0,0 +1,351 @@
+package pchecker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// JSONCensor censors string values (and optionally object keys) of JSON documents.
+// The document is processed as a token stream, so member order, formatting of numbers
+// and their precision are preserved. Selectors use a JSONPath-like syntax:
+//
+//	$.user.name       member "name" of member "user"
+//	$.items[*].text   member "text" of every element of "items"
+//	$.items[0]        first element of "items"
+//	$['odd key']      member with a key that is not a plain identifier
+//	$..comment        member "comment" at any depth
+//
+// A selector selects the node it points to together with everything nested below it.
+type JSONCensor struct {
+	pd      *ProfanityDetector
+	f       ReplacementFunc
+	include []jsonSelector
+	exclude []jsonSelector
+	keys    bool
+	err     error
+}
+
+type jsonSelector []jsonStep
+
+type jsonStep struct {
+	name      string
+	index     int
+	kind      jsonStepKind
+	recursive bool
+}
+
+type jsonStepKind uint8
+
+const (
+	jsonStepName jsonStepKind = iota
+	jsonStepIndex
+	jsonStepWildcard
+)
+
+type jsonPathElem struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+type jsonFrame struct {
+	object  bool
+	wantKey bool
+	count   int
+}
+
+func (pd *ProfanityDetector) NewJSONCensor(f ReplacementFunc) *JSONCensor {
+	return &JSONCensor{
+		pd: pd,
+		f:  f,
+	}
+}
+
+// Include restricts censoring to the nodes matched by the given selectors.
+// Without any include selector every string value is censored
+func (jc *JSONCensor) Include(selectors ...string) *JSONCensor {
+	jc.include = jc.appendSelectors(jc.include, selectors)
+	return jc
+}
+
+// Exclude prevents censoring of the nodes matched by the given selectors, it wins over Include
+func (jc *JSONCensor) Exclude(selectors ...string) *JSONCensor {
+	jc.exclude = jc.appendSelectors(jc.exclude, selectors)
+	return jc
+}
+
+// WithKeys enables censoring of object keys of the selected members
+func (jc *JSONCensor) WithKeys() *JSONCensor {
+	jc.keys = true
+	return jc
+}
+
+func (jc *JSONCensor) appendSelectors(dst []jsonSelector, selectors []string) []jsonSelector {
+	for _, s := range selectors {
+		sel, err := parseJSONSelector(s)
+		if err != nil {
+			if jc.err == nil {
+				jc.err = err
+			}
+			continue
+		}
+		dst = append(dst, sel)
+	}
+	return dst
+}
+
+// Censor returns a censored copy of the JSON document(s) in data
+func (jc *JSONCensor) Censor(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	out.Grow(len(data))
+	if err := jc.CensorStream(&out, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// CensorStream reads a stream of JSON documents from r and writes their censored version to w.
+// Insignificant whitespace is not preserved, consecutive documents are separated by a newline
+func (jc *JSONCensor) CensorStream(w io.Writer, r io.Reader) error {
+	if jc.err != nil {
+		return jc.err
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	bw := bufio.NewWriter(w)
+	stack := make([]jsonFrame, 0, 8)
+	path := make([]jsonPathElem, 0, 8)
+	scratch := make([]byte, 0, 64)
+	docs := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF && len(stack) == 0 {
+			break
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		n := len(stack)
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:n-1]
+			path = path[:n-1]
+			bw.WriteByte(byte(d))
+			docs += valueDone(stack)
+			continue
+		}
+		if n > 0 && stack[n-1].object && stack[n-1].wantKey {
+			key := tok.(string)
+			if stack[n-1].count > 0 {
+				bw.WriteByte(',')
+			}
+			path[n-1] = jsonPathElem{key: key}
+			if jc.keys && jc.selected(path) {
+				key = jc.pd.Censor(key, jc.f)
+			}
+			scratch = appendJSONString(scratch[:0], key)
+			bw.Write(scratch)
+			bw.WriteByte(':')
+			stack[n-1].wantKey = false
+			continue
+		}
+		switch {
+		case n > 0 && !stack[n-1].object:
+			if stack[n-1].count > 0 {
+				bw.WriteByte(',')
+			}
+			path[n-1] = jsonPathElem{index: stack[n-1].count, isIndex: true}
+		case n == 0 && docs > 0:
+			bw.WriteByte('\n')
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			bw.WriteByte(byte(v))
+			stack = append(stack, jsonFrame{object: v == '{', wantKey: true})
+			path = append(path, jsonPathElem{})
+			continue
+		case string:
+			if jc.selected(path) {
+				v = jc.pd.Censor(v, jc.f)
+			}
+			scratch = appendJSONString(scratch[:0], v)
+			bw.Write(scratch)
+		case json.Number:
+			bw.WriteString(v.String())
+		case bool:
+			bw.Write(strconv.AppendBool(scratch[:0], v))
+		case nil:
+			bw.WriteString("null")
+		}
+		docs += valueDone(stack)
+	}
+	return bw.Flush()
+}
+
+// valueDone updates the enclosing container after a complete value,
+// it returns 1 when a top level document has been completed
+func valueDone(stack []jsonFrame) int {
+	n := len(stack)
+	if n == 0 {
+		return 1
+	}
+	stack[n-1].count++
+	if stack[n-1].object {
+		stack[n-1].wantKey = true
+	}
+	return 0
+}
+
+func (jc *JSONCensor) selected(path []jsonPathElem) bool {
+	if len(jc.include) > 0 {
+		found := false
+		for _, sel := range jc.include {
+			if sel.match(path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, sel := range jc.exclude {
+		if sel.match(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// match reports whether the path points to the selected node or to something nested below it
+func (s jsonSelector) match(path []jsonPathElem) bool {
+	if len(s) == 0 {
+		return true
+	}
+	step := s[0]
+	if !step.recursive {
+		return len(path) > 0 && step.matches(path[0]) && s[1:].match(path[1:])
+	}
+	for i := range path {
+		if step.matches(path[i]) && s[1:].match(path[i+1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (st jsonStep) matches(e jsonPathElem) bool {
+	switch st.kind {
+	case jsonStepName:
+		return !e.isIndex && e.key == st.name
+	case jsonStepIndex:
+		return e.isIndex && e.index == st.index
+	default:
+		return true
+	}
+}
+
+func parseJSONSelector(s string) (jsonSelector, error) {
+	rest, ok := strings.CutPrefix(s, "$")
+	if !ok {
+		return nil, fmt.Errorf("pchecker: json selector %q must start with '$'", s)
+	}
+	var result jsonSelector
+	for len(rest) > 0 {
+		var st jsonStep
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			st.recursive = true
+			rest = rest[2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		case rest[0] != '[':
+			return nil, fmt.Errorf("pchecker: unexpected %q in json selector %q", rest[0], s)
+		}
+		if len(rest) > 0 && rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("pchecker: unterminated '[' in json selector %q", s)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				st.kind = jsonStepWildcard
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				st.kind, st.name = jsonStepName, inner[1:len(inner)-1]
+			default:
+				idx, err := strconv.Atoi(inner)
+				if err != nil || idx < 0 {
+					return nil, fmt.Errorf("pchecker: invalid index %q in json selector %q", inner, s)
+				}
+				st.kind, st.index = jsonStepIndex, idx
+			}
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("pchecker: empty member name in json selector %q", s)
+			}
+			if name == "*" {
+				st.kind = jsonStepWildcard
+			} else {
+				st.kind, st.name = jsonStepName, name
+			}
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// appendJSONString appends s as a quoted JSON string without escaping HTML characters
+func appendJSONString(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				dst = append(dst, `�`...)
+			} else {
+				dst = append(dst, s[i:i+size]...)
+			}
+			i += size
+			continue
+		}
+		switch {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c == '\n':
+			dst = append(dst, '\\', 'n')
+		case c == '\r':
+			dst = append(dst, '\\', 'r')
+		case c == '\t':
+			dst = append(dst, '\\', 't')
+		case c < 0x20:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+		i++
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,115 @@
+package pchecker
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+var (
+	ErrInputTooLong = errors.New("pchecker: input too long")
+	ErrTokenTooLong = errors.New("pchecker: token too long")
+)
+
+// LimitAction defines what happens to an input or a token exceeding its limit
+type LimitAction uint8
+
+const (
+	// LimitTruncate drops the input after the limit, or leaves the rest of a token unchanged
+	// and only matches its first runes
+	LimitTruncate LimitAction = iota
+	// LimitReject fails with ErrInputTooLong or ErrTokenTooLong
+	LimitReject
+	// LimitCensor censors the whole input or token, the replacement is given only the runes
+	// within the limit so it does not grow with the input
+	LimitCensor
+)
+
+// Limits bounds the work of CensorContext, a zero limit is unlimited
+type Limits struct {
+	MaxInputLength int // in bytes
+	InputAction    LimitAction
+	MaxTokenLength int // in runes
+	TokenAction    LimitAction
+}
+
+// cancelCheckInterval is the number of runes scanned between two checks of the context
+const cancelCheckInterval = 1024
+
+// WithLimits sets the limits enforced by CensorContext
+func (pd *ProfanityDetector) WithLimits(limits Limits) *ProfanityDetector {
+	pd.limits = limits
+	return pd
+}
+
+// CensorContext is Censor enforcing the configured limits. It stops with the error of the
+// context as soon as ctx is cancelled, the context is checked every few runes
+func (pd *ProfanityDetector) CensorContext(ctx context.Context, input string, f ReplacementFunc) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f = pd.replacementOr(f)
+	if max := pd.limits.MaxInputLength; max > 0 && len(input) > max {
+		switch pd.limits.InputAction {
+		case LimitReject:
+			return "", ErrInputTooLong
+		case LimitCensor:
+			return f([]rune(truncate(input, max))), nil
+		default:
+			input = truncate(input, max)
+		}
+	}
+	return pd.censor(input, f, &scanLimits{ctx: ctx, Limits: pd.limits})
+}
+
+// truncate cuts s to at most max bytes without splitting a rune
+func truncate(s string, max int) string {
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// scanLimits is the state of a scan enforcing limits, a nil *scanLimits enforces nothing
+type scanLimits struct {
+	ctx context.Context
+	Limits
+	runes int // runes scanned so far
+	err   error
+}
+
+// stopped counts a scanned rune and reports whether the scan has to stop
+func (sl *scanLimits) stopped() bool {
+	if sl == nil {
+		return false
+	}
+	if sl.runes++; sl.err == nil && sl.runes%cancelCheckInterval == 0 {
+		sl.err = sl.ctx.Err()
+	}
+	return sl.err != nil
+}
+
+// tokenFull reports whether a token of n runes reached the maximum token length
+func (sl *scanLimits) tokenFull(n int) bool {
+	return sl != nil && sl.MaxTokenLength > 0 && n >= sl.MaxTokenLength
+}
+
+// context returns the context of the scan, context.Background() without limits
+func (sl *scanLimits) context() context.Context {
+	if sl == nil {
+		return context.Background()
+	}
+	return sl.ctx
+}
+
+func (sl *scanLimits) error() error {
+	if sl == nil {
+		return nil
+	}
+	return sl.err
+}
@@ -0,0 +1,139 @@
+package pchecker
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// LintKind is a kind of dictionary issue
+type LintKind string
+
+const (
+	// LintDuplicate is an entry equal to another one after normalization
+	LintDuplicate LintKind = "duplicate"
+	// LintUnreachable is a profanity which is not censored even on its own
+	LintUnreachable LintKind = "unreachable"
+	// LintShadowed is a profanity containing a shorter profanity which already censors it
+	LintShadowed LintKind = "shadowed"
+	// LintUnusedFalsePositive is a false positive not overlapping any profanity
+	LintUnusedFalsePositive LintKind = "unused-false-positive"
+	// LintIneffectiveFalsePositive is a false positive which is censored anyway
+	LintIneffectiveFalsePositive LintKind = "ineffective-false-positive"
+)
+
+// Dictionary lists as named by LintIssue
+const (
+	ListProfanities    = "profanities"
+	ListFalsePositives = "falsePositives"
+	ListFalseNegatives = "falseNegatives"
+)
+
+// LintIssue is a problem found in the dictionaries of a policy
+type LintIssue struct {
+	Kind    LintKind
+	List    string // list of the entry
+	Entry   string
+	Related string // entry causing the issue, if any
+}
+
+func (i LintIssue) String() string {
+	if i.Related == "" {
+		return fmt.Sprintf("%s: %s %q", i.Kind, i.List, i.Entry)
+	}
+	return fmt.Sprintf("%s: %s %q (%s)", i.Kind, i.List, i.Entry, i.Related)
+}
+
+// Lint checks the dictionaries of the policy for entries that can never fire, false positives
+// that don't prevent any match, duplicates after normalization and profanities shadowed by
+// shorter ones. Fuzzy and phonetic matching are disabled to judge the exact entries only.
+// Patterns and phrases are only checked for duplicates
+func Lint(p Policy) ([]LintIssue, error) {
+	p.Fuzzy, p.Phonetic = false, false
+	pd, err := p.NewDetector()
+	if err != nil {
+		return nil, err
+	}
+	lists := []struct {
+		name    string
+		entries map[string]bool
+	}{
+		{ListProfanities, p.Profanities.entries(DefaultProfanities)},
+		{ListFalsePositives, p.FalsePositives.entries(DefaultFalsePositives)},
+		{ListFalseNegatives, p.FalseNegatives.entries(DefaultFalseNegatives)},
+	}
+	var issues []LintIssue
+	normalized := make([]map[string]string, len(lists))
+	for i, list := range lists {
+		normalized[i] = make(map[string]string, len(list.entries))
+		for _, entry := range slices.Sorted(maps.Keys(list.entries)) {
+			word, _ := ParseEntry(entry)
+			key := pd.foldString(word)
+			if first, ok := normalized[i][key]; ok {
+				issues = append(issues, LintIssue{Kind: LintDuplicate, List: list.name, Entry: entry, Related: first})
+				continue
+			}
+			normalized[i][key] = entry
+		}
+	}
+	for key, entry := range normalized[2] {
+		if profanity, ok := normalized[0][key]; ok {
+			issues = append(issues, LintIssue{Kind: LintDuplicate, List: ListFalseNegatives, Entry: entry, Related: profanity})
+		}
+	}
+	for _, entry := range normalized[0] {
+		if word, ok := lintWord(entry); ok {
+			issues = append(issues, pd.lintProfanity(entry, word)...)
+		}
+	}
+	for _, entry := range normalized[1] {
+		if word, ok := lintWord(entry); ok {
+			issues = append(issues, pd.lintFalsePositive(entry, word)...)
+		}
+	}
+	slices.SortFunc(issues, func(a, b LintIssue) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.List, b.List), cmp.Compare(a.Entry, b.Entry), cmp.Compare(a.Related, b.Related))
+	})
+	return issues, nil
+}
+
+// lintWord returns the word of an entry which can be judged as a token on its own
+func lintWord(entry string) (string, bool) {
+	word, _ := ParseEntry(entry)
+	return word, !isPhrase(word) && !isPattern(word)
+}
+
+func (pd *ProfanityDetector) lintProfanity(entry, word string) []LintIssue {
+	var issues []LintIssue
+	pd.scan(word, func(tb *tokenBuffer) {
+		if !tb.censored {
+			issues = append(issues, LintIssue{Kind: LintUnreachable, List: ListProfanities, Entry: entry})
+			return
+		}
+		pd.censoringMatches(tb, func(m runeSpan) {
+			if m.end-m.start < len(tb.norm) {
+				issues = append(issues, LintIssue{Kind: LintShadowed, List: ListProfanities, Entry: entry, Related: pd.info(tb, m).Entry})
+			}
+		})
+	})
+	return issues
+}
+
+func (pd *ProfanityDetector) lintFalsePositive(entry, word string) []LintIssue {
+	var issues []LintIssue
+	pd.scan(word, func(tb *tokenBuffer) {
+		switch {
+		case tb.censored:
+			issues = append(issues, LintIssue{Kind: LintIneffectiveFalsePositive, List: ListFalsePositives, Entry: entry})
+		case len(tb.matches) == 0:
+			issues = append(issues, LintIssue{Kind: LintUnusedFalsePositive, List: ListFalsePositives, Entry: entry})
+		}
+	})
+	return issues
+}
@@ -0,0 +1,445 @@
+package pchecker
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+type markupPieceKind uint8
+
+const (
+	pieceText   markupPieceKind = iota // visible text, the only thing that gets censored
+	pieceInline                        // markup inside a word that is transparent for matching, e.g. <b> or **
+	pieceBreak                         // markup or hidden content that separates words, e.g. <p>, <code>…</code>, link targets
+)
+
+type markupPiece struct {
+	raw     string
+	text    string // decoded visible text of a pieceText
+	offsets []int  // offset in raw of every byte of text followed by len(raw), nil if text is raw
+	kind    markupPieceKind
+}
+
+var (
+	inlineHTMLElements = map[string]bool{
+		"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "big": true, "cite": true, "data": true,
+		"del": true, "dfn": true, "em": true, "font": true, "i": true, "ins": true, "mark": true, "q": true,
+		"s": true, "small": true, "span": true, "strike": true, "strong": true, "sub": true, "sup": true,
+		"time": true, "tt": true, "u": true, "var": true,
+	}
+	// skippedHTMLElements are elements whose content is never censored
+	skippedHTMLElements = map[string]bool{
+		"code": true, "kbd": true, "pre": true, "samp": true,
+	}
+	// rawTextHTMLElements are elements whose content is not markup at all
+	rawTextHTMLElements = map[string]bool{
+		"script": true, "style": true, "template": true,
+	}
+)
+
+// CensorHTML censors only the visible text nodes of an HTML document or fragment.
+// Tags, attribute values, comments and the content of code, pre, script and style elements
+// are left untouched, and words split by inline tags (e.g. "f<b>uck</b>") are still detected
+func (pd *ProfanityDetector) CensorHTML(input string, f ReplacementFunc) string {
+	return pd.censorPieces(input, htmlPieces(input), f, html.EscapeString)
+}
+
+// CensorMarkdown censors only the visible text of a Markdown document.
+// Code spans, fenced and indented code blocks, link destinations, autolinks and inline HTML
+// are left untouched, and words split by emphasis markers (e.g. "f**uck**") are still detected
+func (pd *ProfanityDetector) CensorMarkdown(input string, f ReplacementFunc) string {
+	return pd.censorPieces(input, markdownPieces(input), f, escapeMarkdown)
+}
+
+// escapeMarkdown escapes the ASCII punctuation of a replacement so it is not read as markup,
+// e.g. masking '*' next to emphasis markers
+func escapeMarkdown(s string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return r < utf8.RuneSelf && isASCIIPunct(byte(r)) }) {
+		return s
+	}
+	var result strings.Builder
+	for _, r := range s {
+		if r < utf8.RuneSelf && isASCIIPunct(byte(r)) {
+			result.WriteByte('\\')
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+func (pd *ProfanityDetector) censorPieces(input string, pieces []markupPiece, f ReplacementFunc, escape func(string) string) string {
+	f = pd.replacementOr(f)
+	var result strings.Builder
+	result.Grow(len(input))
+	var run strings.Builder
+	for i := 0; i < len(pieces); {
+		if pieces[i].kind == pieceBreak {
+			result.WriteString(pieces[i].raw)
+			i++
+			continue
+		}
+		j := i
+		run.Reset()
+		for ; j < len(pieces) && pieces[j].kind != pieceBreak; j++ {
+			run.WriteString(pieces[j].text)
+		}
+		pd.censorRun(&result, pieces[i:j], run.String(), f, escape)
+		i = j
+	}
+	return result.String()
+}
+
+type censoredSpan struct {
+	start, end  int
+	replacement string
+}
+
+// censorRun censors the visible text of consecutive text and inline pieces as a whole
+// and writes it back piece by piece, so the markup between them stays intact.
+// The replacements are escaped, the text outside of them is copied as it is
+func (pd *ProfanityDetector) censorRun(result *strings.Builder, pieces []markupPiece, text string, f ReplacementFunc, escape func(string) string) {
+	var spans []censoredSpan
+	pd.scan(text, func(tb *tokenBuffer) {
+		if tb.censored {
+			spans = append(spans, censoredSpan{start: tb.start, end: tb.end, replacement: escape(f(tb.buff))})
+		}
+	})
+	offset := 0
+	for _, p := range pieces {
+		if p.kind != pieceText {
+			result.WriteString(p.raw)
+			continue
+		}
+		start, end := offset, offset+len(p.text)
+		offset = end
+		pos := start
+		for _, s := range spans {
+			if s.end <= start || s.start >= end {
+				continue
+			}
+			if s.start >= start {
+				result.WriteString(textPart(p, pos-start, s.start-start))
+				result.WriteString(s.replacement)
+			}
+			pos = min(s.end, end)
+		}
+		result.WriteString(textPart(p, pos-start, end-start))
+	}
+}
+
+// textPart returns the original form of the part of the piece between the given offsets of its text
+func textPart(p markupPiece, from, to int) string {
+	if p.offsets == nil {
+		return p.raw[from:to]
+	}
+	return p.raw[p.offsets[from]:p.offsets[to]]
+}
+
+func htmlPieces(input string) []markupPiece {
+	pieces := make([]markupPiece, 0, 16)
+	skip := 0 // depth of elements whose content is not censored
+	textStart := 0
+	for i := 0; i < len(input); {
+		if input[i] != '<' {
+			i++
+			continue
+		}
+		end, name, closing, selfClosing := scanHTMLTag(input, i)
+		if end < 0 {
+			i++
+			continue
+		}
+		pieces = appendHTMLText(pieces, input[textStart:i], skip > 0)
+		kind := pieceBreak
+		if skip == 0 && inlineHTMLElements[name] {
+			kind = pieceInline
+		}
+		pieces = append(pieces, markupPiece{raw: input[i:end], kind: kind})
+		i, textStart = end, end
+		switch {
+		case rawTextHTMLElements[name] && !closing && !selfClosing:
+			closeAt := indexFold(input[i:], "</"+name)
+			if closeAt < 0 {
+				closeAt = len(input) - i
+			}
+			pieces = append(pieces, markupPiece{raw: input[i : i+closeAt], kind: pieceBreak})
+			i, textStart = i+closeAt, i+closeAt
+		case skippedHTMLElements[name] && closing:
+			skip = max(skip-1, 0)
+		case skippedHTMLElements[name] && !selfClosing:
+			skip++
+		}
+	}
+	return appendHTMLText(pieces, input[textStart:], skip > 0)
+}
+
+func appendHTMLText(pieces []markupPiece, raw string, skipped bool) []markupPiece {
+	switch {
+	case raw == "":
+		return pieces
+	case skipped:
+		return append(pieces, markupPiece{raw: raw, kind: pieceBreak})
+	}
+	text, offsets := unescapeHTML(raw)
+	return append(pieces, markupPiece{raw: raw, text: text, offsets: offsets, kind: pieceText})
+}
+
+// maxEntityLength is the length of the longest character reference decoded, "&CounterClockwiseContourIntegral;"
+const maxEntityLength = 33
+
+// unescapeHTML decodes the character references of raw. It returns the offset in raw of every
+// byte of the decoded text followed by len(raw), or nil offsets if raw has no references
+func unescapeHTML(raw string) (string, []int) {
+	if !strings.Contains(raw, "&") {
+		return raw, nil
+	}
+	var text strings.Builder
+	offsets := make([]int, 0, len(raw)+1)
+	for i := 0; i < len(raw); {
+		end := i + 1
+		decoded := raw[i:end]
+		if raw[i] == '&' {
+			j := i + 1
+			for j < min(i+maxEntityLength, len(raw)) && (isASCIIAlnum(raw[j]) || raw[j] == '#') {
+				j++
+			}
+			if j < len(raw) && raw[j] == ';' {
+				if reference := raw[i : j+1]; html.UnescapeString(reference) != reference {
+					end, decoded = j+1, html.UnescapeString(reference)
+				}
+			}
+		}
+		text.WriteString(decoded)
+		for range len(decoded) {
+			offsets = append(offsets, i)
+		}
+		i = end
+	}
+	return text.String(), append(offsets, len(raw))
+}
+
+// scanHTMLTag parses the tag starting at input[i] == '<'. It returns the offset just after
+// the tag and its lower-cased name, or a negative offset if there is no tag at i
+func scanHTMLTag(input string, i int) (end int, name string, closing, selfClosing bool) {
+	rest := input[i+1:]
+	switch {
+	case strings.HasPrefix(rest, "!--"):
+		closeAt := strings.Index(rest[3:], "-->")
+		if closeAt < 0 {
+			return len(input), "!--", false, true
+		}
+		return i + 1 + 3 + closeAt + 3, "!--", false, true
+	case strings.HasPrefix(rest, "!") || strings.HasPrefix(rest, "?"):
+		closeAt := strings.IndexByte(rest, '>')
+		if closeAt < 0 {
+			return -1, "", false, false
+		}
+		return i + 1 + closeAt + 1, "!", false, true
+	case strings.HasPrefix(rest, "/"):
+		closing = true
+		rest = rest[1:]
+	}
+	n := 0
+	for n < len(rest) && isASCIIAlnum(rest[n]) {
+		n++
+	}
+	if n == 0 || !isASCIILetter(rest[0]) {
+		return -1, "", false, false
+	}
+	name = strings.ToLower(rest[:n])
+	var quote byte
+	for j := n; j < len(rest); j++ {
+		c := rest[j]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			selfClosing = j > 0 && rest[j-1] == '/'
+			return len(input) - len(rest) + j + 1, name, closing, selfClosing
+		}
+	}
+	return -1, "", false, false
+}
+
+func markdownPieces(input string) []markupPiece {
+	pieces := make([]markupPiece, 0, 16)
+	fence := ""
+	prevBlank, prevCode := true, false
+	for lineStart := 0; lineStart < len(input); {
+		lineEnd := strings.IndexByte(input[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(input)
+		} else {
+			lineEnd += lineStart + 1
+		}
+		line := input[lineStart:lineEnd]
+		lineStart = lineEnd
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			pieces = append(pieces, markupPiece{raw: line, kind: pieceBreak})
+			continue
+		case len(line)-len(trimmed) <= 3 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+			fence = trimmed[:3]
+			pieces = append(pieces, markupPiece{raw: line, kind: pieceBreak})
+			continue
+		}
+		blank := strings.TrimSpace(line) == ""
+		code := !blank && (prevBlank || prevCode) && (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"))
+		prevBlank, prevCode = blank, code
+		if code {
+			pieces = append(pieces, markupPiece{raw: line, kind: pieceBreak})
+			continue
+		}
+		pieces = appendMarkdownInline(pieces, line)
+	}
+	return pieces
+}
+
+func appendMarkdownInline(pieces []markupPiece, line string) []markupPiece {
+	textStart := 0
+	add := func(start, end int, kind markupPieceKind) {
+		if textStart < start {
+			pieces = append(pieces, markupPiece{raw: line[textStart:start], text: line[textStart:start], kind: pieceText})
+		}
+		pieces = append(pieces, markupPiece{raw: line[start:end], kind: kind})
+		textStart = end
+	}
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && isASCIIPunct(line[i+1]):
+			add(i, i+1, pieceInline)
+			i += 2
+		case c == '`':
+			n := countRun(line[i:], '`')
+			closeAt := indexRun(line[i+n:], '`', n)
+			if closeAt < 0 {
+				i += n
+				continue
+			}
+			end := i + n + closeAt + n
+			add(i, end, pieceBreak)
+			i = end
+		case c == '*' || c == '_' || c == '~':
+			n := countRun(line[i:], c)
+			add(i, i+n, pieceInline)
+			i += n
+		case c == ']' && i+1 < len(line) && line[i+1] == '(':
+			closeAt := indexLinkDestinationEnd(line[i+1:])
+			if closeAt < 0 {
+				i++
+				continue
+			}
+			add(i+1, i+1+closeAt+1, pieceBreak)
+			i = i + 1 + closeAt + 1
+		case c == '<':
+			if closeAt := strings.IndexByte(line[i:], '>'); closeAt > 0 && isAutolink(line[i+1:i+closeAt]) {
+				add(i, i+closeAt+1, pieceBreak)
+				i += closeAt + 1
+				continue
+			}
+			end, _, _, _ := scanHTMLTag(line, i)
+			if end < 0 {
+				i++
+				continue
+			}
+			add(i, end, pieceInline)
+			i = end
+		default:
+			i++
+		}
+	}
+	if textStart < len(line) {
+		pieces = append(pieces, markupPiece{raw: line[textStart:], text: line[textStart:], kind: pieceText})
+	}
+	return pieces
+}
+
+// indexLinkDestinationEnd returns the index of the ')' closing the link destination s starts with
+func indexLinkDestinationEnd(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isAutolink(s string) bool {
+	if strings.ContainsAny(s, " \t<") {
+		return false
+	}
+	scheme, _, ok := strings.Cut(s, ":")
+	if ok && len(scheme) >= 2 && isASCIILetter(scheme[0]) {
+		return true
+	}
+	return strings.Contains(s, "@")
+}
+
+func countRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// indexRun returns the index of the first run of exactly n bytes c in s
+func indexRun(s string, c byte, n int) int {
+	for i := 0; i < len(s); {
+		if s[i] != c {
+			i++
+			continue
+		}
+		run := countRun(s[i:], c)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isASCIIAlnum(c byte) bool {
+	return isASCIILetter(c) || c >= '0' && c <= '9'
+}
+
+func isASCIIPunct(c byte) bool {
+	return c >= '!' && c <= '/' || c >= ':' && c <= '@' || c >= '[' && c <= '`' || c >= '{' && c <= '~'
+}
@@ -0,0 +1,133 @@
+package pchecker
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// MatchMode restricts where in a word a dictionary entry is allowed to match.
+// A word is a token, or a segment of it when segmentation is enabled
+type MatchMode uint8
+
+const (
+	// MatchAnywhere matches the entry anywhere inside a word: "ass"
+	MatchAnywhere MatchMode = 0
+	// MatchPrefix matches the entry only at the start of a word: "^ass"
+	MatchPrefix MatchMode = 1
+	// MatchSuffix matches the entry only at the end of a word: "ass$"
+	MatchSuffix MatchMode = 2
+	// MatchWord matches the entry only as a whole word: "^ass$"
+	MatchWord = MatchPrefix | MatchSuffix
+)
+
+// ParseEntry splits a dictionary entry into the word and its match mode,
+// a leading '^' anchors the entry to the start of a word and a trailing '$' to its end
+func ParseEntry(entry string) (string, MatchMode) {
+	mode := MatchAnywhere
+	if word, ok := strings.CutPrefix(entry, "^"); ok && word != "" {
+		entry, mode = word, mode|MatchPrefix
+	}
+	if word, ok := strings.CutSuffix(entry, "$"); ok && word != "" {
+		entry, mode = word, mode|MatchSuffix
+	}
+	return entry, mode
+}
+
+// EntryInfo is the metadata the dictionary tries carry for every word
+type EntryInfo struct {
+	Entry    string // entry as listed in the dictionary, without anchors
+	Mode     MatchMode
+	Severity float64
+	Category Category
+}
+
+// getDictionary builds the trie of a dictionary carrying the match modes of its entries.
+// Entries ending at the same word after normalization keep the least restrictive combination
+// of their modes and the first of them in sorted order as their entry, also when they become
+// equal by a later normalizer. Patterns are expanded into the trie and invalid ones are skipped.
+// Phrases are handled by getPhrases
+func (pd *ProfanityDetector) getDictionary(m map[string]bool) *SafeTrieMap[rune, EntryInfo] {
+	result := NewSafeTrieMap[rune, EntryInfo](len(m)).WithComparator(pd.fold()).WithJoin(joinEntryInfo)
+	for _, entry := range slices.Sorted(maps.Keys(m)) {
+		if isPhrase(entry) {
+			continue
+		}
+		word, mode := ParseEntry(entry)
+		var ends []*node[rune]
+		if pattern, ok := patternOf(word); ok {
+			atoms, err := parsePattern(pattern)
+			if err != nil {
+				continue
+			}
+			pd.patternSeparators = pd.patternSeparators || continuesWords(atoms)
+			ends = insertPattern(&result.SafeTrie, atoms)
+		} else {
+			ends = []*node[rune]{result.insert([]rune(word))}
+		}
+		for _, n := range ends {
+			result.set(n, EntryInfo{Entry: word, Mode: mode, Severity: pd.severity(word), Category: pd.category(word)}, true)
+		}
+	}
+	for _, info := range result.values {
+		pd.anchored = pd.anchored || info.Mode != MatchAnywhere
+	}
+	return result
+}
+
+// joinEntryInfo keeps the first entry with the least restrictive combination of the modes
+func joinEntryInfo(first, later EntryInfo) EntryInfo {
+	first.Mode &= later.Mode
+	return first
+}
+
+// applyMatchModes drops the matches found at a position their entries are not allowed to match at
+func (pd *ProfanityDetector) applyMatchModes(tb *tokenBuffer) {
+	if !pd.anchored {
+		return
+	}
+	outOfPlace := func(t *SafeTrieMap[rune, EntryInfo]) func(m runeSpan) bool {
+		return func(m runeSpan) bool {
+			info, _ := t.value(m.entry)
+			return info.Mode&MatchPrefix != 0 && m.start != 0 || info.Mode&MatchSuffix != 0 && m.end != len(tb.norm)
+		}
+	}
+	tb.matches = slices.DeleteFunc(tb.matches, outOfPlace(pd.profanities))
+	tb.falsePositives = slices.DeleteFunc(tb.falsePositives, outOfPlace(pd.falsePositives))
+	tb.falseNegatives = slices.DeleteFunc(tb.falseNegatives, outOfPlace(pd.falseNegatives))
+}
+
+// info returns the metadata of the dictionary entry of a match. Only phrases have no trie node,
+// their metadata is looked up by name
+func (pd *ProfanityDetector) info(tb *tokenBuffer, m runeSpan) EntryInfo {
+	for _, t := range [...]*SafeTrieMap[rune, EntryInfo]{pd.profanities, pd.falseNegatives, pd.falsePositives} {
+		if info, ok := t.value(m.entry); ok {
+			return info
+		}
+	}
+	entry := m.word
+	if entry == "" {
+		entry = string(tb.norm[m.start:m.end])
+	}
+	return EntryInfo{Entry: entry, Severity: pd.severity(entry), Category: pd.category(entry)}
+}
+
+// annotate updates the severity and category carried by the entries of the dictionaries
+func (pd *ProfanityDetector) annotate() {
+	for _, t := range [...]*SafeTrieMap[rune, EntryInfo]{pd.profanities, pd.falseNegatives, pd.falsePositives} {
+		if t == nil {
+			continue
+		}
+		t.lock.Lock()
+		for n, info := range t.values {
+			info.Severity, info.Category = pd.severity(info.Entry), pd.category(info.Entry)
+			t.values[n] = info
+		}
+		t.lock.Unlock()
+	}
+}
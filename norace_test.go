@@ -0,0 +1,5 @@
+//go:build !race
+
+package pchecker
+
+const raceEnabled = false
@@ -0,0 +1,97 @@
+package pchecker
+
+import "unicode"
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Normalizer maps a rune to the form used by the dictionary tries, it must be idempotent
+type Normalizer func(r rune) rune
+
+// CaseFold folds the case of r with unicode.SimpleFold, preferring the lower case ASCII
+// form, so 'K', 'k' and the Kelvin sign 'K' as well as 'S', 's' and the long 'ſ' are equal
+func CaseFold(r rune) rune {
+	if r <= unicode.MaxASCII {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}
+	result := unicode.ToLower(r)
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f <= unicode.MaxASCII && unicode.IsLower(f) {
+			return f
+		}
+	}
+	return result
+}
+
+// TurkishCaseFold is CaseFold with the Turkish dotted and dotless i, 'I' becomes 'ı' and 'İ' becomes 'i'
+func TurkishCaseFold(r rune) rune {
+	return CaseFold(unicode.TurkishCase.ToLower(r))
+}
+
+// WidthFold maps the fullwidth forms of ASCII characters like 'Ｆ' and the ideographic
+// space to their ASCII counterparts
+func WidthFold(r rune) rune {
+	switch {
+	case '！' <= r && r <= '～':
+		return r - 0xFF01 + '!'
+	case r == '　':
+		return ' '
+	}
+	return r
+}
+
+// Normalizers chains normalizers, each one applied to the result of the previous
+func Normalizers(normalizers ...Normalizer) Normalizer {
+	return func(r rune) rune {
+		for _, n := range normalizers {
+			r = n(r)
+		}
+		return r
+	}
+}
+
+// WithNormalizer sets the normalization of the input and of the dictionary entries,
+// CaseFold by default. Character replacements are looked up on normalized runes, so their
+// keys must be normalized too. The dictionaries configured so far are normalized again
+func (pd *ProfanityDetector) WithNormalizer(normalizer Normalizer) *ProfanityDetector {
+	pd.normalizer = normalizer
+	fold := pd.fold()
+	for _, t := range [...]*SafeTrieMap[rune, EntryInfo]{pd.profanities, pd.falsePositives, pd.falseNegatives} {
+		if t != nil {
+			t.WithComparator(fold)
+		}
+	}
+	if pd.segmentWords != nil {
+		pd.segmentWords.WithComparator(fold)
+	}
+	if pd.phrases != nil {
+		pd.phrases.phrases.WithComparator(pd.foldString)
+		pd.phrases.words.WithComparator(fold)
+	}
+	if pd.phonetic != nil {
+		pd.phonetic.falsePositives.WithComparator(fold)
+		pd.indexPhonetic()
+	}
+	return pd
+}
+
+// fold returns the configured normalizer
+func (pd *ProfanityDetector) fold() func(rune) rune {
+	if pd.normalizer != nil {
+		return pd.normalizer
+	}
+	return CaseFold
+}
+
+func (pd *ProfanityDetector) foldString(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = pd.fold()(r)
+	}
+	return string(runes)
+}
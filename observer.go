@@ -0,0 +1,182 @@
+package pchecker
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+	"time"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Observer is notified of every scan of the detector, Censor, Explain, Score and Validate alike.
+// It is called synchronously, so implementations must be fast and safe for concurrent use.
+// ctx is the context given to CensorContext, CensorBatch or CensorPipeline, context.Background() otherwise
+type Observer interface {
+	// ObserveScan is called once the input has been scanned
+	ObserveScan(ctx context.Context, e ScanEvent)
+	// ObserveMatch is called for every match censoring a token
+	ObserveMatch(ctx context.Context, e MatchEvent)
+}
+
+// ScanEvent describes a scanned input
+type ScanEvent struct {
+	Bytes      int // length of the input
+	Tokens     int
+	Censored   int // tokens censored
+	Suppressed int // tokens with matches kept by false positives, context rules or the severity threshold
+	Duration   time.Duration
+}
+
+// MatchEvent describes a match censoring a token
+type MatchEvent struct {
+	Entry       string
+	Category    Category
+	Severity    float64
+	Approximate bool // found by fuzzy or phonetic matching
+}
+
+// WithObserver sets the observer notified of every scan, scans cost nothing extra without one
+func (pd *ProfanityDetector) WithObserver(o Observer) *ProfanityDetector {
+	pd.observer = o
+	return pd
+}
+
+// scanObserver counts the tokens of a scan for the observer
+type scanObserver struct {
+	pd    *ProfanityDetector
+	ctx   context.Context
+	start time.Time
+	event ScanEvent
+}
+
+func (so *scanObserver) visit(tb *tokenBuffer, next func(tb *tokenBuffer)) {
+	so.event.Tokens++
+	switch {
+	case tb.censored:
+		so.event.Censored++
+		so.pd.censoringMatches(tb, func(m runeSpan) {
+			match := so.pd.match(tb, m)
+			so.pd.observer.ObserveMatch(so.ctx, MatchEvent{
+				Entry:       match.Entry,
+				Category:    match.Category,
+				Severity:    match.Severity,
+				Approximate: m.distance > 0 || m.phonetic,
+			})
+		})
+	case len(tb.matches) > 0:
+		so.event.Suppressed++
+	}
+	next(tb)
+}
+
+func (so *scanObserver) close() {
+	so.event.Duration = time.Since(so.start)
+	so.pd.observer.ObserveScan(so.ctx, so.event)
+}
+
+// latencyBuckets are the upper bounds of the latency histogram buckets of ExpvarObserver
+var latencyBuckets = []time.Duration{
+	10 * time.Microsecond, 100 * time.Microsecond, time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond,
+}
+
+// ExpvarObserver publishes counters as an expvar map:
+//
+//	scans, bytes, tokens, censored, suppressed   totals
+//	matches                                      matches by category
+//	severities                                   matches by severity
+//	latency                                      scans by latency bucket, e.g. "le_1ms" or "inf"
+type ExpvarObserver struct {
+	scans, bytes, tokens, censored, suppressed expvar.Int
+	matches, severities, latency               expvar.Map
+}
+
+// NewExpvarObserver publishes the counters under name, an existing map with that name is reused
+func NewExpvarObserver(name string) *ExpvarObserver {
+	o := &ExpvarObserver{}
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		m = expvar.NewMap(name)
+	}
+	m.Set("scans", &o.scans)
+	m.Set("bytes", &o.bytes)
+	m.Set("tokens", &o.tokens)
+	m.Set("censored", &o.censored)
+	m.Set("suppressed", &o.suppressed)
+	m.Set("matches", o.matches.Init())
+	m.Set("severities", o.severities.Init())
+	m.Set("latency", o.latency.Init())
+	return o
+}
+
+func (o *ExpvarObserver) ObserveScan(_ context.Context, e ScanEvent) {
+	o.scans.Add(1)
+	o.bytes.Add(int64(e.Bytes))
+	o.tokens.Add(int64(e.Tokens))
+	o.censored.Add(int64(e.Censored))
+	o.suppressed.Add(int64(e.Suppressed))
+	o.latency.Add(latencyBucket(e.Duration), 1)
+}
+
+func (o *ExpvarObserver) ObserveMatch(_ context.Context, e MatchEvent) {
+	o.matches.Add(string(e.Category), 1)
+	o.severities.Add(strconv.FormatFloat(e.Severity, 'g', -1, 64), 1)
+}
+
+func latencyBucket(d time.Duration) string {
+	for _, bound := range latencyBuckets {
+		if d <= bound {
+			return "le_" + bound.String()
+		}
+	}
+	return "inf"
+}
+
+// OTelInstruments records the observations to OpenTelemetry instruments. The instruments are
+// wrapped in functions so the detector does not depend on OpenTelemetry:
+//
+//	scans, _ := meter.Int64Counter("pchecker.scans")
+//	matches, _ := meter.Int64Counter("pchecker.matches")
+//	latency, _ := meter.Float64Histogram("pchecker.latency", metric.WithUnit("s"))
+//	pd.WithObserver(pchecker.NewOTelObserver(pchecker.OTelInstruments{
+//		Scans: func(ctx context.Context, e pchecker.ScanEvent) { scans.Add(ctx, 1) },
+//		Matches: func(ctx context.Context, e pchecker.MatchEvent) {
+//			matches.Add(ctx, 1, metric.WithAttributes(attribute.String("category", string(e.Category))))
+//		},
+//		Latency: func(ctx context.Context, seconds float64) { latency.Record(ctx, seconds) },
+//	}))
+//
+// Missing functions are skipped
+type OTelInstruments struct {
+	Scans   func(ctx context.Context, e ScanEvent)
+	Matches func(ctx context.Context, e MatchEvent)
+	Latency func(ctx context.Context, seconds float64)
+}
+
+type otelObserver struct {
+	instruments OTelInstruments
+}
+
+// NewOTelObserver returns an observer recording to OpenTelemetry instruments with the context of
+// the scan, so baggage, exemplars and trace links of the request are kept
+func NewOTelObserver(instruments OTelInstruments) Observer {
+	return &otelObserver{instruments: instruments}
+}
+
+func (o *otelObserver) ObserveScan(ctx context.Context, e ScanEvent) {
+	if o.instruments.Scans != nil {
+		o.instruments.Scans(ctx, e)
+	}
+	if o.instruments.Latency != nil {
+		o.instruments.Latency(ctx, e.Duration.Seconds())
+	}
+}
+
+func (o *otelObserver) ObserveMatch(ctx context.Context, e MatchEvent) {
+	if o.instruments.Matches != nil {
+		o.instruments.Matches(ctx, e)
+	}
+}
@@ -0,0 +1,229 @@
+package pchecker
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// maxPatternExpansion limits the number of words a single pattern entry may expand to
+const maxPatternExpansion = 4096
+
+// patternAtom is a single position of a pattern entry repeated min to max times
+type patternAtom struct {
+	runes    []rune // alternatives, nil for the wildcard
+	min, max int
+}
+
+// patternMarker starts the word of a pattern entry, other entries are plain words
+const patternMarker = "~"
+
+// isPattern reports whether the entry uses the pattern syntax
+func isPattern(word string) bool {
+	_, ok := patternOf(word)
+	return ok
+}
+
+// patternOf returns the pattern of a word starting with the pattern marker
+func patternOf(word string) (string, bool) {
+	pattern, ok := strings.CutPrefix(word, patternMarker)
+	return pattern, ok && pattern != ""
+}
+
+// ValidateEntry reports whether a dictionary entry is valid. Besides plain words and the
+// '^' and '$' anchors of ParseEntry, entries starting with '~' use a restricted pattern syntax:
+//
+//	.       any single rune
+//	[abc]   one of the listed runes, ranges like [a-z] are allowed
+//	x?      optional x
+//	x{n}    x repeated n times
+//	x{m,n}  x repeated m to n times
+//	\x      literal x
+//
+// Invalid entries are skipped by the dictionary loaders. Punctuation listed in a pattern of the
+// profanities or false negatives, like '!' in "~sh[i1!]t", and any punctuation but whitespace at
+// a wildcard no longer separate words where the pattern can match them, so "sh!t" and "f*ck" are found
+func ValidateEntry(entry string) error {
+	word, _ := ParseEntry(entry)
+	pattern, ok := patternOf(word)
+	if !ok {
+		return nil
+	}
+	_, err := parsePattern(pattern)
+	return err
+}
+
+// continuesWords reports whether the pattern can match punctuation, which then does not always end a word
+func continuesWords(atoms []patternAtom) bool {
+	return slices.ContainsFunc(atoms, func(a patternAtom) bool {
+		return a.runes == nil || slices.ContainsFunc(a.runes, isSeparator)
+	})
+}
+
+func parsePattern(pattern string) ([]patternAtom, error) {
+	var atoms []patternAtom
+	quantified := false
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch r {
+		case '.':
+			atoms = append(atoms, patternAtom{min: 1, max: 1})
+			quantified = false
+		case '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) || end == i+1 {
+				return nil, fmt.Errorf("pchecker: invalid character class in pattern %q", pattern)
+			}
+			class, err := parseClass(runes[i+1 : end])
+			if err != nil {
+				return nil, fmt.Errorf("pchecker: %w in pattern %q", err, pattern)
+			}
+			atoms = append(atoms, patternAtom{runes: class, min: 1, max: 1})
+			quantified = false
+			i = end
+		case '?', '{':
+			if len(atoms) == 0 || quantified {
+				return nil, fmt.Errorf("pchecker: misplaced %q in pattern %q", r, pattern)
+			}
+			last := &atoms[len(atoms)-1]
+			if r == '?' {
+				last.min = 0
+			} else {
+				end := i + 1
+				for end < len(runes) && runes[end] != '}' {
+					end++
+				}
+				if end == len(runes) {
+					return nil, fmt.Errorf("pchecker: unterminated repeat in pattern %q", pattern)
+				}
+				minRep, maxRep, err := parseRepeat(string(runes[i+1 : end]))
+				if err != nil {
+					return nil, fmt.Errorf("pchecker: %w in pattern %q", err, pattern)
+				}
+				last.min, last.max = minRep, maxRep
+				i = end
+			}
+			quantified = true
+		case '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("pchecker: trailing '\\' in pattern %q", pattern)
+			}
+			i++
+			atoms = append(atoms, patternAtom{runes: []rune{runes[i]}, min: 1, max: 1})
+			quantified = false
+		default:
+			atoms = append(atoms, patternAtom{runes: []rune{r}, min: 1, max: 1})
+			quantified = false
+		}
+	}
+	total, minLen := 1, 0
+	for _, a := range atoms {
+		alternatives := max(len(a.runes), 1)
+		variants, power := 0, 1
+		for k := 0; k <= a.max; k++ {
+			if k >= a.min {
+				variants += power
+			}
+			power *= alternatives
+		}
+		total *= variants
+		minLen += a.min
+		if total > maxPatternExpansion {
+			return nil, fmt.Errorf("pchecker: pattern %q expands to more than %d words", pattern, maxPatternExpansion)
+		}
+	}
+	if minLen == 0 {
+		return nil, fmt.Errorf("pchecker: pattern %q matches the empty word", pattern)
+	}
+	return atoms, nil
+}
+
+func parseClass(class []rune) ([]rune, error) {
+	var result []rune
+	for i := 0; i < len(class); i++ {
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] > class[i+2] || class[i+2]-class[i] > 128 {
+				return nil, fmt.Errorf("invalid range %q", string(class[i:i+3]))
+			}
+			for r := class[i]; r <= class[i+2]; r++ {
+				result = append(result, r)
+			}
+			i += 2
+			continue
+		}
+		result = append(result, class[i])
+	}
+	return result, nil
+}
+
+func parseRepeat(s string) (int, int, error) {
+	minStr, maxStr, hasMax := strings.Cut(s, ",")
+	minRep, err := strconv.Atoi(minStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid repeat {%s}", s)
+	}
+	maxRep := minRep
+	if hasMax {
+		if maxRep, err = strconv.Atoi(maxStr); err != nil {
+			return 0, 0, fmt.Errorf("invalid repeat {%s}", s)
+		}
+	}
+	if minRep < 0 || maxRep < minRep || maxRep == 0 {
+		return 0, 0, fmt.Errorf("invalid repeat {%s}", s)
+	}
+	return minRep, maxRep, nil
+}
+
+// insertPattern adds every word matched by the pattern to the trie and returns the nodes they end at
+func insertPattern(t *SafeTrie[rune], atoms []patternAtom) []*node[rune] {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var ends []*node[rune]
+	var insert func(n *node[rune], atoms []patternAtom)
+	insert = func(n *node[rune], atoms []patternAtom) {
+		if len(atoms) == 0 {
+			if !n.isEnd {
+				n.isEnd = true
+				t.size++
+			}
+			ends = append(ends, n)
+			return
+		}
+		a := atoms[0]
+		var repeat func(n *node[rune], k int)
+		repeat = func(n *node[rune], k int) {
+			if k >= a.min {
+				insert(n, atoms[1:])
+			}
+			if k == a.max {
+				return
+			}
+			if a.runes == nil {
+				if n.wildcard == nil {
+					n.wildcard = &node[rune]{children: make(map[rune]*node[rune])}
+				}
+				repeat(n.wildcard, k+1)
+				return
+			}
+			for _, r := range a.runes {
+				if isSeparator(r) && !slices.Contains(n.separators, r) {
+					n.separators = append(n.separators, r)
+				}
+				repeat(n.child(t.compare(r)), k+1)
+			}
+		}
+		repeat(n, 0)
+	}
+	insert(t.root, atoms)
+	return ends
+}
@@ -2,8 +2,8 @@ package pchecker
 
 type ReplacementFunc func(match []rune) string
 
-func getSafeTrie(m map[string]bool) *SafeTrie[rune] {
-	result := NewSafeTrie[rune](len(m))
+func getSafeTrie(m map[string]bool, comparator func(rune) rune) *SafeTrie[rune] {
+	result := NewSafeTrie[rune](len(m)).WithComparator(comparator)
 	for word := range m {
 		result.Insert([]rune(word))
 	}
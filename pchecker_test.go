@@ -1,9 +1,23 @@
 package pchecker
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"expvar"
+	"iter"
+	"log/slog"
+	"maps"
+	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode"
 )
 
 /**
@@ -168,6 +182,18 @@ func TestProfanityDetector_Censor(t *testing.T) {
 			input:    "press the button",
 			expected: "press the button",
 		},
+		{
+			input:    "myglass firstclass",
+			expected: "myglass firstclass",
+		},
+		{
+			input:    "assistshit",
+			expected: "***",
+		},
+		{
+			input:    "what*the*fuck shit*",
+			expected: "what*the**** ****",
+		},
 	}
 	pd := NewDefaultProfanityDetector()
 	for _, tt := range tests {
@@ -305,3 +331,1160 @@ func TestPrintAll(t *testing.T) {
 		}).PrintAll()
 	})
 }
+
+func TestJSONCensor_Censor(t *testing.T) {
+	pd := NewDefaultProfanityDetector()
+	tests := []struct {
+		name     string
+		jc       *JSONCensor
+		input    string
+		expected string
+	}{
+		{
+			name:     "all strings",
+			jc:       pd.NewJSONCensor(f),
+			input:    `{"b": "fuck this", "a": [1.50000000000000000001, "shit", null, true], "fuck": "hello"}`,
+			expected: `{"b":"*** this","a":[1.50000000000000000001,"***",null,true],"fuck":"hello"}`,
+		},
+		{
+			name:     "keys",
+			jc:       pd.NewJSONCensor(f).WithKeys(),
+			input:    `{"fuck": {"shit": "ok"}}`,
+			expected: `{"***":{"***":"ok"}}`,
+		},
+		{
+			name:     "include and exclude",
+			jc:       pd.NewJSONCensor(f).Include("$.items[*].text", "$..comment").Exclude("$.items[1]"),
+			input:    `{"title": "shit", "items": [{"text": "shit", "id": "shit"}, {"text": "shit"}], "deep": {"x": [{"comment": "fuck <b>"}]}}`,
+			expected: `{"title":"shit","items":[{"text":"***","id":"shit"},{"text":"shit"}],"deep":{"x":[{"comment":"*** <b>"}]}}`,
+		},
+		{
+			name:     "stream",
+			jc:       pd.NewJSONCensor(f).Include("$['odd key']"),
+			input:    `{"odd key": "shit\n"} ["shit"]`,
+			expected: "{\"odd key\":\"***\\n\"}\n[\"shit\"]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			censored, err := tt.jc.Censor([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(censored) != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	if _, err := pd.NewJSONCensor(f).Include("items").Censor([]byte(`{}`)); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+	if _, err := pd.NewJSONCensor(f).Censor([]byte(`{"a": `)); err == nil {
+		t.Error("expected error for truncated document")
+	}
+}
+
+func TestProfanityDetector_CensorHTML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `<p class="shit">f<b>uck</b> you</p>`,
+			expected: `<p class="shit">***<b></b> you</p>`,
+		},
+		{
+			input:    `<a href="https://example.com/shit" title="fuck">shit &amp; glass</a>`,
+			expected: `<a href="https://example.com/shit" title="fuck">*** &amp; glass</a>`,
+		},
+		{
+			input:    `<pre>shit</pre><code>fuck</code><script>var fuck = "<b>";</script><!-- shit -->`,
+			expected: `<pre>shit</pre><code>fuck</code><script>var fuck = "<b>";</script><!-- shit -->`,
+		},
+		{
+			input:    `<p>fu</p><p>ck</p> sh<br>it &quot;shit&quot;`,
+			expected: `<p>fu</p><p>ck</p> sh<br>it &quot;***&quot;`,
+		},
+		{
+			input:    `Tom&#39;s &eacute;t&eacute; &amp shit&#x21; &bogus; fuck`,
+			expected: `Tom&#39;s &eacute;t&eacute; &amp ***&#x21; &bogus; ***`,
+		},
+		{
+			input:    `<title>shit</title><textarea>fuck you</textarea>`,
+			expected: `<title>***</title><textarea>*** you</textarea>`,
+		},
+	}
+	pd := NewDefaultProfanityDetector()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.CensorHTML(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_CensorMarkdown(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "# f**uck** this\n\nsay `shit` and [shit](https://example.com/shit) <https://shit.com>",
+			expected: "# \\*\\*\\***** this\n\nsay `shit` and [\\*\\*\\*](https://example.com/shit) <https://shit.com>",
+		},
+		{
+			input:    "```\nshit\n```\n\n    fuck\n\nshit",
+			expected: "```\nshit\n```\n\n    fuck\n\n\\*\\*\\*",
+		},
+	}
+	pd := NewDefaultProfanityDetector()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.CensorMarkdown(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_CensorEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		pd       *ProfanityDetector
+		input    string
+		expected string
+	}{
+		{
+			name:     "not recognized by default",
+			pd:       NewDefaultProfanityDetector(),
+			input:    "www.fuckyou.com mail shit@example.com fuck.you@x.co `fuck`",
+			expected: "www.***.com mail ***.com ***.you@x.co ***",
+		},
+		{
+			name:     "default policies",
+			pd:       NewDefaultProfanityDetector().WithDefaultEntityPolicies(),
+			input:    "mail fuck.you@example.com, see https://example.com/shit_list (or www.shit.com). @shit_lord #fuckthis `shit` g@y",
+			expected: "mail ***, see *** (or ***). @*** #*** `***` ***",
+		},
+		{
+			name: "skip",
+			pd: NewDefaultProfanityDetector().
+				WithEntityPolicy(EntityURL, EntitySkip).
+				WithEntityPolicy(EntityCode, EntitySkip),
+			input:    "https://example.com/shit `shit` shit",
+			expected: "https://example.com/shit `shit` ***",
+		},
+		{
+			name: "censor",
+			pd: NewDefaultProfanityDetector().
+				WithEntityPolicy(EntityURL, EntityCensor).
+				WithEntityPolicy(EntityMention, EntityCensor),
+			input:    "https://example.com/shit https://example.com @shit_lord @friend",
+			expected: "*** https://example.com *** @friend",
+		},
+		{
+			name: "scan",
+			pd: NewDefaultProfanityDetector().
+				WithEntityPolicy(EntityEmail, EntityScan).
+				WithEntityPolicy(EntityCode, EntityScan),
+			input:    "fuck.you@example.com `x = shit`",
+			expected: "***.you@example.com `x = ***`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if censored := tt.pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_CensorSegments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "BigAssTruck glassHouse",
+			expected: "Big***Truck glassHouse",
+		},
+		{
+			input:    "#goFuckYourself #gofuckyourself",
+			expected: "#go***Yourself #go***yourself",
+		},
+		{
+			input:    "shit22 sh1tHead fuckfuck",
+			expected: "***22 ***Head ***",
+		},
+		{
+			input:    "#classicrock #passion",
+			expected: "#classicrock #passion",
+		},
+	}
+	pd := NewDefaultProfanityDetector().
+		WithDefaultEntityPolicies().
+		WithSegmentation(map[string]bool{"go": true, "your": true, "self": true})
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_Explain(t *testing.T) {
+	explanations := NewDefaultProfanityDetector().Explain("Sh1t, an assassin massterbait myglass")
+	expected := []string{
+		`"Sh1t" [0:4] normalized "shit", matches "shit" [0:4]: censored`,
+		`"an" [6:8] normalized "an": kept`,
+		`"assassin" [9:17] normalized "assassin", matches "ass" [9:12] "ass" [12:15], false positives "assassin" [9:17]: kept`,
+		`"massterbait" [18:29] normalized "massterbait", matches "ass" [19:22] "massterbait" [18:29], false positives "mass" [18:22], false negatives "masst" [18:23]: censored`,
+		`"myglass" [30:37] normalized "myglass", matches "ass" [34:37], false positives "glass" [32:37] "lass" [33:37]: kept`,
+	}
+	if len(explanations) != len(expected) {
+		t.Fatalf("expected %d explanations, got %d: %v", len(expected), len(explanations), explanations)
+	}
+	for i, e := range explanations {
+		if e.String() != expected[i] {
+			t.Errorf("expected '%s', got '%s'", expected[i], e)
+		}
+	}
+}
+
+func TestProfanityDetector_CensorMatchModes(t *testing.T) {
+	pd := NewDefaultProfanityDetector().
+		WithProfanities(map[string]bool{"^anal": true, "ass$": true, "^tit$": true, "shit": true}).
+		WithFalsePositives(map[string]bool{"^analy": true})
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "canal anal analysis analog",
+			expected: "canal *** analysis ***",
+		},
+		{
+			input:    "bass assign badass",
+			expected: "*** assign ***",
+		},
+		{
+			input:    "tit title bullshit",
+			expected: "*** title ***",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	if word, mode := ParseEntry("^ass$"); word != "ass" || mode != MatchWord {
+		t.Errorf("expected 'ass' matching whole words, got '%s' %d", word, mode)
+	}
+}
+
+func TestProfanityDetector_CensorPatterns(t *testing.T) {
+	pd := NewProfanityDetector().
+		WithProfanities(map[string]bool{"~f.ck": true, "~sh[i!1]t": true, "~bu?tt": true, "^~a{2,3}rgh$": true, "~d[a-c]mn": true, "what?": true}).
+		WithFalsePositives(map[string]bool{"~f.ckle": true})
+	fpPattern := NewDefaultProfanityDetector().WithFalsePositives(map[string]bool{"~f.ckle": true})
+	segmented := NewProfanityDetector().WithProfanities(map[string]bool{"~sh[i!]t": true}).WithSegmentation(nil)
+	tests := []struct {
+		pd       *ProfanityDetector
+		input    string
+		expected string
+	}{
+		{
+			input:    "f*ck fack fck fickle f-ck",
+			expected: "*** *** fck fickle ***",
+		},
+		{
+			input:    "shit sh1t sh!t shot sh?t",
+			expected: "*** *** *** shot sh?t",
+		},
+		{
+			input:    "butt btt but aargh aaargh aaaargh",
+			expected: "*** *** but *** *** aaaargh",
+		},
+		{
+			input:    "damn dbmn ddmn",
+			expected: "*** *** ddmn",
+		},
+		{
+			input:    "what*the*damn",
+			expected: "what*the****",
+		},
+		{
+			input:    "what? wha what",
+			expected: "what? wha what",
+		},
+		{
+			pd:       segmented,
+			input:    "BigSh!tTruck sh!",
+			expected: "Big***Truck sh!",
+		},
+		{
+			pd:       fpPattern,
+			input:    "what*the*fuck, shit!",
+			expected: "what*the****, ***!",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			pd := cmp.Or(tt.pd, pd)
+			if censored := pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	for _, entry := range []string{"~sh[it", "~a{3,1}", "~?a", "~x?", "~a{2}{3}", "~[a-z]{3}[a-z]{3}", "~a\\"} {
+		if ValidateEntry(entry) == nil {
+			t.Errorf("expected '%s' to be invalid", entry)
+		}
+	}
+	for _, entry := range []string{"^~f[u*]ck\\.$", "sh[it", "what?"} {
+		if err := ValidateEntry(entry); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestProfanityDetector_CensorFuzzy(t *testing.T) {
+	pd := NewDefaultProfanityDetector().WithDefaultFuzzy()
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "a blowjbo, a bastrad and a scortum",
+			expected: "a ***, a *** and a ***",
+		},
+		{
+			input:    "a truck, an ass, a glass and the puck",
+			expected: "a truck, an ***, a glass and the puck",
+		},
+		{
+			input:    "fukc this btich, you azzhole",
+			expected: "*** this ***, you ***",
+		},
+		{
+			input:    "a carp in the artist's result, stuff to pronounce on mars",
+			expected: "a carp in the artist's result, stuff to pronounce on mars",
+		},
+		{
+			input:    "duck dock deck shot shut shirt shift count cant batch butch bitten dice cook coke bunt rap rapper tots",
+			expected: "duck dock deck shot shut shirt shift count cant batch butch bitten dice cook coke bunt rap rapper tots",
+		},
+		{
+			input:    "regard reward walker wander banker witches maggot bigger",
+			expected: "regard reward walker wander banker witches maggot bigger",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	explanations := pd.Explain("bastrad")
+	if len(explanations) != 1 || len(explanations[0].Matches) != 1 {
+		t.Fatalf("expected a single match, got %v", explanations)
+	}
+	if m := explanations[0].Matches[0]; m.Entry != "bastard" || m.Distance != 1 || m.Start != 0 || m.End != 7 {
+		t.Errorf("expected 'bastard' at distance 1, got %+v", m)
+	}
+	if m := pd.Explain("azzhole")[0].Matches[0]; m.Entry != "asshole" || m.Distance != 1 {
+		t.Errorf("expected look-alikes to count as half an edit, got %+v", m)
+	}
+}
+
+func TestProfanityDetector_CensorPhonetic(t *testing.T) {
+	pd := NewDefaultProfanityDetector().WithDefaultPhonetic()
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "phuck this sheeyit, kunt",
+			expected: "*** this ***, ***",
+		},
+		{
+			input:    "peenis and bollox, cunnt wanck, wanquer",
+			expected: "*** and ***, *** ***, ***",
+		},
+		{
+			input:    "but the count wore tights in a batch of pennies",
+			expected: "but the count wore tights in a batch of pennies",
+		},
+		{
+			input:    "rap rapper wrapping coke coax chit dike annual annals sheet shout boat bait hour beach peach pitch penny pansy spank",
+			expected: "rap rapper wrapping coke coax chit dike annual annals sheet shout boat bait hour beach peach pitch penny pansy spank",
+		},
+		{
+			input:    "a pub pup in a boom boot, worse and worrying, juicing, cunning airs",
+			expected: "a pub pup in a boom boot, worse and worrying, juicing, cunning airs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	if e := pd.Explain("cunnt")[0]; len(e.Matches) != 1 || e.Matches[0].Entry != "cunt" || !e.Matches[0].Phonetic {
+		t.Errorf("expected a phonetic match of 'cunt', got %v", e)
+	}
+}
+
+func TestProfanityDetector_CensorPhrases(t *testing.T) {
+	pd := NewDefaultProfanityDetector()
+	custom := NewProfanityDetector().WithProfanities(map[string]bool{"go kill yourself": true, "kill": true, "kill time": true})
+	tests := []struct {
+		pd       *ProfanityDetector
+		input    string
+		expected string
+	}{
+		{pd: pd, input: "just kill yourself", expected: "just ***"},
+		{pd: pd, input: "Kill... YOURSELF!", expected: "***!"},
+		{pd: pd, input: "suck my toe", expected: "*** toe"},
+		{pd: pd, input: "kill time yourself", expected: "kill time yourself"},
+		{pd: pd, input: "kill", expected: "kill"},
+		{pd: custom, input: "go kill yourself now", expected: "*** now"},
+		{pd: custom, input: "go kill time", expected: "go ***"},
+		{pd: custom, input: "go kill", expected: "go ***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := tt.pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	if e := pd.Explain("well, kill yourself"); len(e) != 2 || e[1].Token != "kill yourself" || e[1].Matches[0].Entry != "kill yourself" {
+		t.Errorf("expected the phrase to be explained as a single token, got %v", e)
+	}
+}
+
+func TestProfanityDetector_CensorContextRules(t *testing.T) {
+	pd := NewDefaultProfanityDetector().WithDefaultContextRules()
+	clinical := NewDefaultProfanityDetector().WithClinicalProfile()
+	tests := []struct {
+		pd       *ProfanityDetector
+		input    string
+		expected string
+	}{
+		{pd: pd, input: "early breast cancer screening", expected: "early breast cancer screening"},
+		{pd: pd, input: "Cancers of the breast", expected: "Cancers of the breast"},
+		{pd: pd, input: "nice breast", expected: "nice ***"},
+		{pd: pd, input: "breast and more words before cancer", expected: "*** and more words before cancer"},
+		{pd: pd, input: "treating an anal fissure", expected: "treating an anal fissure"},
+		{pd: pd, input: "anal fissure, shit", expected: "anal fissure, ***"},
+		{pd: clinical, input: "breast, penis and vagina", expected: "breast, penis and vagina"},
+		{pd: clinical, input: "breast shit", expected: "breast ***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if censored := tt.pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_Score(t *testing.T) {
+	pd := NewDefaultProfanityDetector().WithDefaultFuzzy()
+	if s := pd.Score("have a nice day"); s != (Score{}) {
+		t.Errorf("expected a zero score, got %+v", s)
+	}
+	mild := pd.Score("what a crap day it was")
+	strong := pd.Score("what a fucking day it was")
+	if mild.Value <= 0 || mild.Value >= strong.Value {
+		t.Errorf("expected 0 < %v < %v", mild.Value, strong.Value)
+	}
+	evaded := pd.Score("what a fu<king day it was")
+	if evaded.Evasion != 1 || evaded.Value <= strong.Value {
+		t.Errorf("expected leetspeak to raise the score, got %+v", evaded)
+	}
+	repeated := pd.Score("fuck fuck fuck")
+	if repeated.Matches != 3 || repeated.Density != 1 || repeated.Repetition != 2.0/3 || repeated.Value >= 1 {
+		t.Errorf("unexpected score %+v", repeated)
+	}
+	if s := pd.Score("fu<k, bastrad"); s.Evasion != 1 {
+		t.Errorf("expected leetspeak and misspelling to count as evasion, got %+v", s)
+	}
+}
+
+func TestNewDetectorFromPolicy(t *testing.T) {
+	err := LoadPolicies(strings.NewReader(`[{
+		"name": "forum",
+		"profanities": {"defaults": true, "add": ["^noob$", "dang it"], "remove": ["crap"]},
+		"falsePositives": {"defaults": true},
+		"characterReplacements": {"0": "o"},
+		"replacement": {"strategy": "fixed", "text": "[removed]"},
+		"entities": true
+	}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		policy   string
+		input    string
+		expected string
+	}{
+		{policy: "default", input: "fuck this crap", expected: "**** this ****"},
+		{policy: "default", input: "https://example.com/assets/class.css", expected: "https://example.com/******/class.css"},
+		{policy: "kids", input: "damn, shit", expected: "****, ****"},
+		{policy: "kids", input: "a duck in a shirt can count", expected: "a duck in a shirt can count"},
+		{policy: "gaming", input: "fuck this crap", expected: "f*** this crap"},
+		{policy: "clinical", input: "breast exam, shit", expected: "breast exam, ****"},
+		{policy: "forum", input: "n00b, dang it, crap, 5hit", expected: "[removed], [removed], crap, 5hit"},
+		{policy: "forum", input: "see https://example.com/assets/class.css", expected: "see [removed]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			if tt.policy == "default" && NewDefaultProfanityDetector().Censor(tt.input, nil) != tt.expected {
+				t.Errorf("expected the default policy to censor like the default detector")
+			}
+			pd, err := NewDetectorFromPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if censored := pd.Censor(tt.input, nil); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+	if _, err := NewDetectorFromPolicy("missing"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+	invalid := []Policy{
+		{},
+		{Name: "a", Replacement: Replacement{Strategy: "blur"}},
+		{Name: "b", CharacterReplacements: map[string]string{"ph": "f"}},
+		{Name: "c", Profanities: Dictionary{Add: []string{"~sh[it"}}},
+	}
+	for _, p := range invalid {
+		if err := RegisterPolicy(p); err == nil {
+			t.Errorf("expected policy %+v to be rejected", p)
+		}
+	}
+	err = LoadPolicies(strings.NewReader(`[{"name": "forum", "profanities": {"add": ["darn"]}}, {"name": "broken", "replacement": {"strategy": "blur"}}]`))
+	if err == nil {
+		t.Error("expected an error for an invalid policy")
+	}
+	if forum, _ := LookupPolicy("forum"); slices.Contains(forum.Profanities.Add, "darn") {
+		t.Error("expected a failed load to leave the registered policies unchanged")
+	}
+}
+
+func TestProfanityDetector_Validate(t *testing.T) {
+	pd := NewDefaultProfanityDetector()
+	if err := pd.Validate("a perfectly fine title"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	err := pd.Validate("you bitch, fucking nigger")
+	var pe *ProfanityError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected a *ProfanityError, got %v", err)
+	}
+	if len(pe.Violations) != 3 || pe.Violations[1].Token != "fucking" || pe.Violations[1].Start != 11 || pe.Violations[1].Matches[0].Entry != "fuck" {
+		t.Errorf("unexpected violations %+v", pe.Violations)
+	}
+	if expected := []Category{CategoryInsult, CategoryProfanity, CategorySlur}; !slices.Equal(pe.Categories(), expected) {
+		t.Errorf("expected categories %v, got %v", expected, pe.Categories())
+	}
+	if expected := `pchecker: input contains profanity "bitch" at [4:9], "fucking" at [11:18], "nigger" at [19:25]`; err.Error() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, err)
+	}
+	type form struct {
+		Title string
+		Tags  []string
+		Note  *string
+	}
+	note := "shit happens"
+	tests := []struct {
+		value    any
+		expected bool
+	}{
+		{value: form{Title: "hello", Tags: []string{"a", "b"}}, expected: true},
+		{value: form{Title: "hello", Tags: []string{"a", "cunt"}}, expected: false},
+		{value: form{Title: "hello", Note: &note}, expected: false},
+	}
+	for _, tt := range tests {
+		v := reflect.ValueOf(tt.value)
+		valid := true
+		for i := range v.NumField() {
+			valid = valid && pd.ValidField(v.Field(i))
+		}
+		if valid != tt.expected {
+			t.Errorf("expected %v for %+v", tt.expected, tt.value)
+		}
+	}
+}
+
+func TestSafeTrie_Query(t *testing.T) {
+	trie := NewSafeTrie[rune](8)
+	for _, word := range []string{"he", "hell", "hello", "help", "world"} {
+		trie.Insert([]rune(word))
+	}
+	trie.Insert([]rune("hell"))
+	if trie.Len() != 5 {
+		t.Errorf("expected 5 words, got %d", trie.Len())
+	}
+	collect := func(seq iter.Seq[[]rune]) []string {
+		var result []string
+		for word := range seq {
+			result = append(result, string(word))
+		}
+		slices.Sort(result)
+		return result
+	}
+	if words := collect(trie.All()); !slices.Equal(words, []string{"he", "hell", "hello", "help", "world"}) {
+		t.Errorf("unexpected words %v", words)
+	}
+	if words := collect(trie.KeysWithPrefix([]rune("hel"))); !slices.Equal(words, []string{"hell", "hello", "help"}) {
+		t.Errorf("unexpected words %v", words)
+	}
+	if n, ok := trie.LongestPrefixOf([]rune("hellish")); !ok || n != 4 {
+		t.Errorf("expected the prefix 'hell', got %d %v", n, ok)
+	}
+	if _, ok := trie.LongestPrefixOf([]rune("wor")); ok {
+		t.Error("expected no prefix")
+	}
+	walked := 0
+	trie.Walk(func([]rune) bool {
+		walked++
+		return walked < 2
+	})
+	if walked != 2 {
+		t.Errorf("expected the walk to stop after 2 words, got %d", walked)
+	}
+	if !trie.Delete([]rune("hell")) || trie.Delete([]rune("hell")) || trie.Exists([]rune("hell")) || !trie.Exists([]rune("hello")) {
+		t.Error("expected only 'hell' to be deleted")
+	}
+	if !trie.Delete([]rune("world")) || trie.IsPrefixInTrie([]rune("w")) || trie.Len() != 3 {
+		t.Error("expected 'world' to be pruned")
+	}
+
+	m := NewMatcher(trie)
+	var all, longest []TrieMatch
+	for match := range m.FindAll([]rune("oh hello help")) {
+		all = append(all, match)
+	}
+	for match := range m.FindLongest([]rune("oh hello help")) {
+		longest = append(longest, match)
+	}
+	if expected := []TrieMatch{{3, 5}, {3, 8}, {9, 11}, {9, 13}}; !slices.Equal(all, expected) {
+		t.Errorf("expected %v, got %v", expected, all)
+	}
+	if expected := []TrieMatch{{3, 8}, {9, 13}}; !slices.Equal(longest, expected) {
+		t.Errorf("expected %v, got %v", expected, longest)
+	}
+	if m.Contains([]rune("world")) {
+		t.Error("expected no match")
+	}
+}
+
+func TestSafeTrieMap(t *testing.T) {
+	trie := NewSafeTrieMap[rune, int](4).WithComparator(unicode.ToLower)
+	trie.Put([]rune("he"), 1)
+	trie.Put([]rune("hell"), 2)
+	trie.Put([]rune("hello"), 3)
+	trie.Put([]rune("hell"), 4)
+	if v, ok := trie.Get([]rune("HELL")); !ok || v != 4 {
+		t.Errorf("expected 4, got %d %v", v, ok)
+	}
+	if _, ok := trie.Get([]rune("hel")); ok {
+		t.Error("expected no value for a prefix")
+	}
+	if n, v, ok := trie.LongestPrefixOf([]rune("hellish")); !ok || n != 4 || v != 4 {
+		t.Errorf("expected 'hell' with 4, got %d %d %v", n, v, ok)
+	}
+	values := make(map[string]int)
+	for word, v := range trie.WithPrefix([]rune("HEL")) {
+		values[string(word)] = v
+	}
+	if !maps.Equal(values, map[string]int{"hell": 4, "hello": 3}) {
+		t.Errorf("unexpected values %v", values)
+	}
+	if !trie.Delete([]rune("hell")) || trie.Len() != 2 {
+		t.Error("expected 'hell' to be deleted")
+	}
+	if _, ok := trie.Get([]rune("hell")); ok {
+		t.Error("expected no value for a deleted word")
+	}
+
+	pd := NewDefaultProfanityDetector().WithProfanities(map[string]bool{"^~sh[i1]t": true, "rape$": true})
+	m := pd.Explain("sh1tty")[0].Matches[0]
+	if m.Entry != "~sh[i1]t" || m.Severity != DefaultSeverity || m.Category != CategoryProfanity {
+		t.Errorf("expected the pattern entry with its metadata, got %+v", m)
+	}
+	if m := pd.Explain("rape")[0].Matches[0]; m.Severity != 3 || m.Category != CategoryViolence {
+		t.Errorf("expected the metadata of 'rape', got %+v", m)
+	}
+	if info, _ := pd.profanities.Get([]rune("rape")); info.Severity != 3 || info.Category != CategoryViolence {
+		t.Errorf("expected the entry to carry its metadata, got %+v", info)
+	}
+	pd.WithSeverities(map[string]float64{"rape": 1.5}).WithCategories(nil)
+	if info, _ := pd.profanities.Get([]rune("rape")); info.Severity != 1.5 || info.Category != CategoryProfanity {
+		t.Errorf("expected the entry metadata to follow the configuration, got %+v", info)
+	}
+
+	concurrent := NewSafeTrieMap[rune, int](0)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			concurrent.Put([]rune(strconv.Itoa(i)), i+1)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			if v, ok := concurrent.Get([]rune(strconv.Itoa(i))); ok && v == 0 {
+				t.Errorf("expected '%d' to have its value once it exists", i)
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestProfanityDetector_CensorNormalizers(t *testing.T) {
+	mixed := NewProfanityDetector().
+		WithProfanities(map[string]bool{"Fuck": true, "^fUCK": true, "^SHIT$": true, "ſcrew": true, "KILL Yourself": true}).
+		WithFalsePositives(map[string]bool{"SHITake": true})
+	turkish := NewDefaultProfanityDetector().
+		WithProfanities(map[string]bool{"sıç": true, "İbne": true}).
+		WithNormalizer(TurkishCaseFold)
+	wide := NewDefaultProfanityDetector().WithNormalizer(Normalizers(WidthFold, CaseFold))
+	tests := []struct {
+		name     string
+		pd       *ProfanityDetector
+		input    string
+		expected string
+	}{
+		{name: "mixed case dictionary", pd: mixed, input: "fuck FUCK Fuck", expected: "*** *** ***"},
+		{name: "mixed case anchors", pd: mixed, input: "Shit shitake shitty", expected: "*** shitake shitty"},
+		{name: "long s", pd: mixed, input: "screw SCREW", expected: "*** ***"},
+		{name: "anchors merged across case", pd: mixed, input: "motherfucker", expected: "***"},
+		{name: "kelvin sign", pd: mixed, input: "Kill yourself", expected: "***"},
+		{name: "dotless i", pd: turkish, input: "SIÇ ibne", expected: "*** ***"},
+		{name: "dotted i", pd: turkish, input: "sic İBNE", expected: "sic ***"},
+		{name: "fullwidth", pd: wide, input: "ｆｕｃｋ ｙｏｕ", expected: "*** ｙｏｕ"},
+		{name: "fullwidth leetspeak", pd: wide, input: "ｓｈ１ｔ sh1t", expected: "*** ***"},
+		{name: "fullwidth without width folding", pd: NewDefaultProfanityDetector(), input: "ｆｕｃｋ", expected: "ｆｕｃｋ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if censored := tt.pd.Censor(tt.input, f); censored != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, censored)
+			}
+		})
+	}
+
+	trie := NewSafeTrie[rune](4)
+	trie.Insert([]rune("Hello"))
+	trie.Insert([]rune("HELLO"))
+	if trie.Len() != 2 || trie.WithComparator(unicode.ToLower).Len() != 1 || !trie.Exists([]rune("hElLo")) {
+		t.Error("expected the existing words to be merged by the comparator")
+	}
+	trie.Insert([]rune("WORLD"))
+	if !trie.Exists([]rune("world")) {
+		t.Error("expected the comparator to be applied on insert")
+	}
+
+	for range 50 {
+		pd := NewProfanityDetector().
+			WithProfanities(map[string]bool{"^ｆｕｃｋ$": true, "fuck": true}).
+			WithNormalizer(Normalizers(WidthFold, CaseFold))
+		if censored := pd.Censor("motherfucker", f); censored != "***" {
+			t.Fatalf("expected the modes to be joined when normalizing, got '%s'", censored)
+		}
+		m := NewSafeTrieMap[rune, int](2)
+		m.Put([]rune("ab"), 1)
+		m.Put([]rune("AB"), 2)
+		if v, _ := m.WithComparator(CaseFold).Get([]rune("ab")); v != 1 {
+			t.Fatalf("expected the value put first, got %d", v)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	issues, err := Lint(Policy{
+		Name:           "lint",
+		Profanities:    Dictionary{Add: []string{"fuck", "^Fuck", "fuckface", "crap", "damn", "~sh[i1]t"}},
+		FalsePositives: Dictionary{Add: []string{"crap", "scrappy", "hello", "damnation"}},
+		FalseNegatives: Dictionary{Add: []string{"DAMN", "damnation"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []LintIssue{
+		{Kind: LintDuplicate, List: ListFalseNegatives, Entry: "DAMN", Related: "damn"},
+		{Kind: LintDuplicate, List: ListProfanities, Entry: "fuck", Related: "^Fuck"},
+		{Kind: LintIneffectiveFalsePositive, List: ListFalsePositives, Entry: "damnation"},
+		{Kind: LintShadowed, List: ListProfanities, Entry: "fuckface", Related: "Fuck"},
+		{Kind: LintUnreachable, List: ListProfanities, Entry: "crap"},
+		{Kind: LintUnusedFalsePositive, List: ListFalsePositives, Entry: "hello"},
+	}
+	if !slices.Equal(issues, expected) {
+		t.Errorf("expected\n%v\ngot\n%v", expected, issues)
+	}
+	if _, err := Lint(Policy{Name: "invalid", Replacement: Replacement{Strategy: "blur"}}); err == nil {
+		t.Error("expected an error for an invalid policy")
+	}
+}
+
+type recordingObserver struct {
+	mu      sync.Mutex
+	scans   []ScanEvent
+	matches []MatchEvent
+}
+
+func (o *recordingObserver) ObserveScan(_ context.Context, e ScanEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.scans = append(o.scans, e)
+}
+
+func (o *recordingObserver) ObserveMatch(_ context.Context, e MatchEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.matches = append(o.matches, e)
+}
+
+func TestProfanityDetector_WithObserver(t *testing.T) {
+	o := &recordingObserver{}
+	pd := NewDefaultProfanityDetector().WithObserver(o)
+	pd.Censor("you bitch, kill yourself in the bass", f)
+	if len(o.scans) != 1 {
+		t.Fatalf("expected one scan, got %v", o.scans)
+	}
+	if e := o.scans[0]; e.Bytes != 36 || e.Tokens != 6 || e.Censored != 2 || e.Suppressed != 1 || e.Duration <= 0 {
+		t.Errorf("unexpected scan %+v", e)
+	}
+	expected := []MatchEvent{
+		{Entry: "bitch", Category: CategoryInsult, Severity: 2},
+		{Entry: "kill yourself", Category: CategoryViolence, Severity: 3},
+	}
+	if !slices.Equal(o.matches, expected) {
+		t.Errorf("expected %v, got %v", expected, o.matches)
+	}
+
+	ev := NewExpvarObserver("pchecker_test")
+	pd.WithObserver(ev)
+	pd.Censor("shit shit", f)
+	pd.Censor("fine", f)
+	vars := expvar.Get("pchecker_test").(*expvar.Map)
+	if scans := vars.Get("scans").String(); scans != "2" {
+		t.Errorf("expected 2 scans, got %s", scans)
+	}
+	if matches := vars.Get("matches").(*expvar.Map).Get("profanity").String(); matches != "2" {
+		t.Errorf("expected 2 profanity matches, got %s", matches)
+	}
+	if NewExpvarObserver("pchecker_test") == nil {
+		t.Error("expected the map to be reused")
+	}
+
+	type requestKey struct{}
+	var latencies []float64
+	var categories []Category
+	var requests []any
+	pd.WithObserver(NewOTelObserver(OTelInstruments{
+		Scans:   func(ctx context.Context, _ ScanEvent) { requests = append(requests, ctx.Value(requestKey{})) },
+		Matches: func(_ context.Context, e MatchEvent) { categories = append(categories, e.Category) },
+		Latency: func(_ context.Context, seconds float64) { latencies = append(latencies, seconds) },
+	}))
+	pd.Censor("nigger", f)
+	if len(latencies) != 1 || !slices.Equal(categories, []Category{CategorySlur}) {
+		t.Errorf("unexpected observations %v %v", latencies, categories)
+	}
+	ctx := context.WithValue(context.Background(), requestKey{}, "request")
+	if _, err := pd.CensorContext(ctx, "shit", f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pd.CensorBatch(ctx, []string{"fine"}, BatchOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(requests, []any{nil, "request", "request"}) {
+		t.Errorf("expected the context of the scan, got %v", requests)
+	}
+}
+
+func TestProfanityDetector_NewSlogHandler(t *testing.T) {
+	pd := NewDefaultProfanityDetector()
+	var out strings.Builder
+	newLogger := func(h *SlogHandler) *slog.Logger {
+		return slog.New(h)
+	}
+	text := slog.NewTextHandler(&out, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	tests := []struct {
+		name     string
+		logger   *slog.Logger
+		log      func(l *slog.Logger)
+		expected string
+	}{
+		{
+			name:   "all keys",
+			logger: newLogger(pd.NewSlogHandler(text, f)),
+			log: func(l *slog.Logger) {
+				l.With("user", "shithead").WithGroup("chat").Info("fuck this", "text", "you bitch", "count", 3,
+					slog.Group("reply", "text", "crap"))
+			},
+			expected: `level=INFO msg="*** this" user=*** chat.text="you ***" chat.count=3 chat.reply.text=***`,
+		},
+		{
+			name:   "selected keys",
+			logger: newLogger(pd.NewSlogHandler(text, f).WithKeys("text", "reply")),
+			log: func(l *slog.Logger) {
+				l.Info("fuck", "user", "shithead", "text", "shit", slog.Group("reply", "body", "crap"))
+			},
+			expected: `level=INFO msg=*** user=shithead text=*** reply.body=***`,
+		},
+		{
+			name:   "selected group",
+			logger: newLogger(pd.NewSlogHandler(text, f).WithKeys("user")),
+			log: func(l *slog.Logger) {
+				l.With("text", "shit").WithGroup("user").With("bio", "crap").Info("fine", "name", "fuck")
+			},
+			expected: `level=INFO msg=fine text=shit user.bio=*** user.name=***`,
+		},
+		{
+			name:   "log valuer",
+			logger: slog.New(text),
+			log: func(l *slog.Logger) {
+				l.Info("received", "text", pd.UserText("no shit", nil))
+			},
+			expected: `level=INFO msg=received text="no ****"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			tt.log(tt.logger)
+			if got := strings.TrimSpace(out.String()); got != tt.expected {
+				t.Errorf("expected '%s', got '%s'", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProfanityDetector_CensorBatch(t *testing.T) {
+	pd := NewDefaultProfanityDetector()
+	inputs := make([]string, 500)
+	expected := make([]string, len(inputs))
+	for i := range inputs {
+		inputs[i] = strings.Repeat("fuck this ", i%7) + strconv.Itoa(i)
+		expected[i] = pd.Censor(inputs[i], f)
+	}
+	results, err := pd.CensorBatch(context.Background(), inputs, BatchOptions{Workers: 4, Replacement: f})
+	if err != nil || !slices.Equal(results, expected) {
+		t.Errorf("expected the sequential results, got %v", err)
+	}
+	if results, err := pd.CensorBatch(context.Background(), nil, BatchOptions{}); err != nil || len(results) != 0 {
+		t.Errorf("expected no results, got %v %v", results, err)
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pd.CensorBatch(cancelled, inputs, BatchOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+
+	var piped []string
+	for text, err := range pd.CensorPipeline(context.Background(), slices.Values(inputs), BatchOptions{Workers: 3, Replacement: f}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		piped = append(piped, text)
+	}
+	if !slices.Equal(piped, expected) {
+		t.Error("expected the pipeline to preserve the order")
+	}
+	for text := range pd.CensorPipeline(context.Background(), slices.Values(inputs), BatchOptions{Replacement: f}) {
+		if text != expected[0] {
+			t.Errorf("expected '%s', got '%s'", expected[0], text)
+		}
+		break
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var last error
+	n := 0
+	for _, err := range pd.CensorPipeline(ctx, slices.Values(inputs), BatchOptions{Workers: 2}) {
+		if n++; n == 10 {
+			cancel()
+		}
+		last = err
+	}
+	if !errors.Is(last, context.Canceled) || n > len(inputs) {
+		t.Errorf("expected the pipeline to end with the context error, got %v after %d results", last, n)
+	}
+
+	timeout, stop := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer stop()
+	var returned atomic.Bool
+	idle := func(yield func(string) bool) {
+		defer returned.Store(true)
+		if !yield("fuck") {
+			return
+		}
+		<-timeout.Done()
+	}
+	var yielded []string
+	for text, err := range pd.CensorPipeline(timeout, idle, BatchOptions{Workers: 2, Replacement: f}) {
+		if err != nil {
+			last = err
+			break
+		}
+		yielded = append(yielded, text)
+	}
+	if !errors.Is(last, context.DeadlineExceeded) || !slices.Equal(yielded, []string{"***"}) {
+		t.Errorf("expected the idle pipeline to end with the context error, got %v %v", last, yielded)
+	}
+	if !returned.Load() {
+		t.Error("expected the pipeline to wait for the source")
+	}
+
+	received := make(chan string, 1)
+	received <- "fuck"
+	defer close(received)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range pd.CensorPipeline(context.Background(), func(yield func(string) bool) {
+			for text := range received {
+				if !yield(text) {
+					return
+				}
+			}
+		}, BatchOptions{Workers: 2}) {
+			break
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("expected an early break to end the pipeline while the source is idle")
+	}
+}
+
+func TestProfanityDetector_CensorContext(t *testing.T) {
+	long := strings.Repeat("a", 20)
+	tests := []struct {
+		name     string
+		limits   Limits
+		input    string
+		expected string
+		err      error
+	}{
+		{"no limits", Limits{}, "fuck " + long, "*** " + long, nil},
+		{"input truncated", Limits{MaxInputLength: 9}, "fuck this shit", "*** this", nil},
+		{"input truncated on a rune boundary", Limits{MaxInputLength: 7}, "fuck ñé", "*** ñ", nil},
+		{"input rejected", Limits{MaxInputLength: 9, InputAction: LimitReject}, "fuck this shit", "", ErrInputTooLong},
+		{"input censored", Limits{MaxInputLength: 9, InputAction: LimitCensor}, "fuck this shit", "***", nil},
+		{"input within the limit", Limits{MaxInputLength: 9, InputAction: LimitReject}, "fuck this", "*** this", nil},
+		{"token truncated", Limits{MaxTokenLength: 4}, "fuckeverything shitty", "***everything ***ty", nil},
+		{"truncated rest not matched", Limits{MaxTokenLength: 4}, "abcdfuck fuck", "abcdfuck ***", nil},
+		{"token rejected", Limits{MaxTokenLength: 10, TokenAction: LimitReject}, "fuck " + long, "", ErrTokenTooLong},
+		{"token censored", Limits{MaxTokenLength: 10, TokenAction: LimitCensor}, "hello " + long + " world", "hello *** world", nil},
+		{"token at the limit", Limits{MaxTokenLength: 5, TokenAction: LimitReject}, "hello fuck", "hello ***", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pd := NewDefaultProfanityDetector().WithLimits(tt.limits)
+			result, err := pd.CensorContext(context.Background(), tt.input, f)
+			if !errors.Is(err, tt.err) || result != tt.expected {
+				t.Errorf("expected '%s' %v, got '%s' %v", tt.expected, tt.err, result, err)
+			}
+		})
+	}
+
+	var lengths []int
+	counting := func(match []rune) string {
+		lengths = append(lengths, len(match))
+		return replacementStr
+	}
+	pd := NewDefaultProfanityDetector().WithLimits(Limits{MaxTokenLength: 10, TokenAction: LimitCensor})
+	if result, _ := pd.CensorContext(context.Background(), "fuck "+strings.Repeat(long, 1000), counting); result != "*** ***" {
+		t.Errorf("unexpected result '%s'", result)
+	}
+	pd.WithLimits(Limits{MaxInputLength: 9, InputAction: LimitCensor})
+	if result, _ := pd.CensorContext(context.Background(), strings.Repeat(long, 1000), counting); result != "***" {
+		t.Errorf("unexpected result '%s'", result)
+	}
+	if !slices.Equal(lengths, []int{4, 10, 9}) {
+		t.Errorf("expected the replacement to get the runes within the limits, got %v", lengths)
+	}
+
+	pd = NewDefaultProfanityDetector()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pd.CensorContext(ctx, "fuck", f); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the context error, got %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	huge := strings.Repeat("fuck this ", 1_000_000)
+	if _, err := pd.CensorContext(ctx, huge, f); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the scan to stop at the deadline, got %v", err)
+	}
+}
+
+func TestProfanityDetector_CensorBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		f        ReplacementFunc
+		expected string
+	}{
+		{"clean", "hello world", f, "hello world"},
+		{"censored", "fuck this shit!", f, "*** this ***!"},
+		{"default mask", "fuck this shit!", nil, "**** this ****!"},
+		{"multibyte", "ñfuck é", nil, "***** é"},
+		{"invalid utf8 copied", "a\xffb fuck", f, "a\xffb ***"},
+		{"invalid utf8 in a censored token", "\xfffuck fu\xffck", nil, "***** fu\xffck"},
+		{"empty", "", f, ""},
+	}
+	pd := NewDefaultProfanityDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := string(pd.CensorBytes(nil, []byte(tt.input), tt.f)); result != tt.expected {
+				t.Errorf("expected '%q', got '%q'", tt.expected, result)
+			}
+			if tt.f != nil {
+				if result := pd.Censor(tt.input, tt.f); result != tt.expected {
+					t.Errorf("expected Censor to agree, got '%q'", result)
+				}
+			}
+			if result := string(pd.AppendCensored([]byte("> "), []byte(tt.input), tt.f)); result != "> "+tt.expected {
+				t.Errorf("expected '> %q', got '%q'", tt.expected, result)
+			}
+		})
+	}
+
+	if raceEnabled {
+		return
+	}
+	src := []byte("you are a fucking idiot and a piece of shit")
+	dst := make([]byte, 0, 2*len(src))
+	if allocs := testing.AllocsPerRun(100, func() { dst = pd.CensorBytes(dst, src, nil) }); allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { dst = pd.CensorBytes(dst, src, f) }); allocs != 0 {
+		t.Errorf("expected no allocations with a constant replacement, got %v", allocs)
+	}
+}
@@ -0,0 +1,283 @@
+package pchecker
+
+import (
+	"slices"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// DefaultPhoneticFalsePositives is a list of common words sounding like a profanity, or like one
+// with an inflection suffix ("worse" like "whores"). A word starting with any of them is never
+// matched phonetically
+var DefaultPhoneticFalsePositives = map[string]bool{
+	"airs":    true,
+	"annal":   true,
+	"annu":    true, // annual
+	"batch":   true,
+	"boom":    true,
+	"boot":    true,
+	"but":     true, // butter, button
+	"chit":    true, // chitin
+	"coax":    true,
+	"coke":    true,
+	"cunning": true,
+	"dike":    true,
+	"juic":    true, // juice, juicing
+	"pub":     true,
+	"pup":     true,
+	"rap":     true, // rapper
+	"shiite":  true,
+	"tight":   true,
+	"wore":    true,
+	"worr":    true, // worried, worrying
+	"worse":   true,
+	"wrap":    true,
+}
+
+// phoneticSuffixes are the keys of inflection suffixes a phonetically matched word may end with
+var phoneticSuffixes = [...]string{"S", "ES", "ER", "ERS", "ED", "IN", "ING", "INS"}
+
+// minPhoneticKeyLength is the length of the shortest key matched, shorter keys like the one of
+// "ass" or "gay" sound like too many words. Keys of three sounds like the one of "rape" are
+// guarded by the phonetic false positives ("rap")
+const minPhoneticKeyLength = 3
+
+// phoneticIndex maps the phonetic keys of the profanities to the profanities
+type phoneticIndex struct {
+	keys           map[string]phoneticEntry
+	falsePositives *SafeTrie[rune]
+}
+
+// phoneticEntry is a normalized profanity and the trie node it ends at
+type phoneticEntry struct {
+	word string
+	end  *node[rune]
+}
+
+// WithPhonetic enables matching of words sounding like a profanity, e.g. "phuck", "sheeyit", "kunt"
+// or "wanquer", by comparing their phonetic keys, or their keys without an inflection suffix, with
+// the keys of at least three sounds of the profanities configured so far. Sharing a key, the word
+// starts with the same sound, however it is spelled. Words starting with a phonetic false positive
+// are never matched phonetically
+func (pd *ProfanityDetector) WithPhonetic(falsePositives map[string]bool) *ProfanityDetector {
+	pd.phonetic = &phoneticIndex{
+		falsePositives: getSafeTrie(falsePositives, pd.fold()),
+	}
+	pd.indexPhonetic()
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultPhonetic() *ProfanityDetector {
+	return pd.WithPhonetic(DefaultPhoneticFalsePositives)
+}
+
+// indexPhonetic computes the phonetic keys of the profanities, on collisions the
+// alphabetically first profanity wins
+func (pd *ProfanityDetector) indexPhonetic() {
+	if pd.phonetic == nil {
+		return
+	}
+	var entries []phoneticEntry
+	if pd.profanities != nil {
+		pd.profanities.lock.RLock()
+		pd.profanities.walkNodes(pd.profanities.root, nil, func(word []rune, end *node[rune]) bool {
+			entries = append(entries, phoneticEntry{word: string(word), end: end})
+			return true
+		})
+		pd.profanities.lock.RUnlock()
+	}
+	slices.SortFunc(entries, func(a, b phoneticEntry) int { return strings.Compare(a.word, b.word) })
+	keys := make(map[string]phoneticEntry, len(entries))
+	var key []byte
+	for _, e := range entries {
+		key = phoneticKey(key[:0], []rune(e.word))
+		if _, exists := keys[string(key)]; !exists && len(key) >= minPhoneticKeyLength {
+			keys[string(key)] = e
+		}
+	}
+	pd.phonetic.keys = keys
+}
+
+// phoneticMatch appends a match covering the whole token if it sounds like a profanity
+func (pd *ProfanityDetector) phoneticMatch(tb *tokenBuffer) {
+	if len(tb.matches) > 0 || startsWithWord(pd.phonetic.falsePositives, tb.norm) {
+		return
+	}
+	tb.key = phoneticKey(tb.key[:0], tb.norm)
+	entry, ok := pd.phonetic.keys[string(tb.key)]
+	for _, suffix := range phoneticSuffixes {
+		if ok {
+			break
+		}
+		if stem, found := strings.CutSuffix(string(tb.key), suffix); found {
+			entry, ok = pd.phonetic.keys[stem]
+		}
+	}
+	if ok {
+		tb.matches = append(tb.matches, runeSpan{start: 0, end: len(tb.norm), entry: entry.end, word: entry.word, phonetic: true})
+	}
+}
+
+// startsWithWord reports whether any word of the trie is a prefix of arr
+func startsWithWord(t *SafeTrie[rune], arr []rune) bool {
+	if t == nil {
+		return false
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	n := t.root
+	for _, r := range arr {
+		child, ok := n.children[r]
+		if !ok {
+			return false
+		}
+		if n = child; n.isEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// phoneticKey appends the phonetic key of the normalized word to dst. The key is a simplified
+// Metaphone: consonants are reduced to their sound, silent letters are dropped and every run of
+// vowels is reduced to its last vowel, so "phuck" and "fuck" or "sheeyit" and "shit" share a key
+func phoneticKey(dst []byte, word []rune) []byte {
+	at := func(i int) rune {
+		if i < 0 || i >= len(word) {
+			return 0
+		}
+		return word[i]
+	}
+	emit := func(codes ...byte) {
+		for _, c := range codes {
+			if len(dst) == 0 || dst[len(dst)-1] != c {
+				dst = append(dst, c)
+			}
+		}
+	}
+	for i := 0; i < len(word); i++ {
+		c, next := word[i], at(i+1)
+		switch c {
+		case 'a', 'e', 'i', 'o', 'u', 'y':
+			j := i
+			for isVowel(at(j + 1)) {
+				j++
+			}
+			// a single trailing 'e' is silent
+			if !(c == 'e' && i == j && j == len(word)-1 && len(dst) > 0) {
+				emit(vowelSound(word[i : j+1]))
+			}
+			i = j
+		case 'b', 'd', 'j', 'l', 'm', 'n', 'r':
+			emit(byte(c) - 'a' + 'A')
+		case 'c':
+			switch {
+			case next == 'h':
+				emit('X')
+				i++
+			case next == 'e' || next == 'i' || next == 'y':
+				emit('S')
+			default:
+				emit('K')
+			}
+		case 'f', 'v':
+			emit('F')
+		case 'g':
+			switch {
+			case next == 'h':
+				if i == 0 {
+					emit('G')
+				}
+				i++
+			case next == 'e' || next == 'i' || next == 'y':
+				emit('J')
+			default:
+				emit('G')
+			}
+		case 'h':
+			if !isConsonant(at(i-1)) && isVowel(next) {
+				emit('H')
+			}
+		case 'k':
+			if i > 0 || next != 'n' {
+				emit('K')
+			}
+		case 'p':
+			if next == 'h' {
+				emit('F')
+				i++
+			} else {
+				emit('P')
+			}
+		case 'q':
+			emit('K')
+		case 's':
+			switch {
+			case next == 'h':
+				emit('X')
+				i++
+			case next == 'c' && at(i+2) == 'h':
+				emit('S', 'K')
+				i += 2
+			default:
+				emit('S')
+			}
+		case 't':
+			switch {
+			case next == 'h':
+				emit('0')
+				i++
+			case next == 'c' && at(i+2) == 'h':
+			default:
+				emit('T')
+			}
+		case 'w':
+			switch {
+			case next == 'h':
+				emit('W')
+				i++
+			case i == 0 && next == 'r':
+			case isVowel(next):
+				emit('W')
+			}
+		case 'x':
+			emit('K', 'S')
+		case 'z':
+			emit('S')
+		}
+	}
+	return dst
+}
+
+// vowelDigraphs are the sounds of the vowel pairs not sounding like their last vowel
+var vowelDigraphs = map[[2]rune]byte{
+	{'a', 'i'}: 'A', {'a', 'y'}: 'A', {'e', 'a'}: 'E', {'e', 'e'}: 'E', {'e', 'i'}: 'E',
+	{'i', 'e'}: 'E', {'o', 'a'}: 'O', {'o', 'o'}: 'U', {'o', 'u'}: 'O',
+}
+
+// vowelSound returns the key of a run of vowels, which is the sound of its last
+// vowel or of its last two vowels if they form a digraph
+func vowelSound(run []rune) byte {
+	if len(run) >= 2 {
+		if sound, ok := vowelDigraphs[[2]rune{run[len(run)-2], run[len(run)-1]}]; ok {
+			return sound
+		}
+	}
+	last := run[len(run)-1]
+	if last == 'y' {
+		last = 'i'
+	}
+	return byte(last) - 'a' + 'A'
+}
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r) && r != 0
+}
+
+func isConsonant(r rune) bool {
+	return r >= 'a' && r <= 'z' && !isVowel(r)
+}
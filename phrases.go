@@ -0,0 +1,144 @@
+package pchecker
+
+import (
+	"strings"
+	"unicode"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// phraseIndex holds the dictionary entries consisting of several words
+type phraseIndex struct {
+	phrases *SafeTrie[string]
+	words   *SafeTrie[rune] // every word of the phrases, to skip the other tokens cheaply
+}
+
+// getPhrases indexes the dictionary entries consisting of several words,
+// it returns nil if there are none
+func (pd *ProfanityDetector) getPhrases(m map[string]bool) *phraseIndex {
+	var result *phraseIndex
+	for entry := range m {
+		if !isPhrase(entry) {
+			continue
+		}
+		if result == nil {
+			result = &phraseIndex{
+				phrases: NewSafeTrie[string](len(m)).WithComparator(pd.foldString),
+				words:   NewSafeTrie[rune](len(m)).WithComparator(pd.fold()),
+			}
+		}
+		words := strings.Fields(entry)
+		result.phrases.Insert(words)
+		for _, word := range words {
+			result.words.Insert([]rune(word))
+		}
+	}
+	return result
+}
+
+// isWord reports whether the normalized token is a word of any phrase
+func (pi *phraseIndex) isWord(norm []rune) bool {
+	n := pi.words.find(norm)
+	return n != nil && n.isEnd
+}
+
+func isPhrase(entry string) bool {
+	return strings.ContainsFunc(strings.TrimSpace(entry), unicode.IsSpace)
+}
+
+// phraseWalk is a walk of the phrase trie that started at the pending token first
+type phraseWalk struct {
+	n     *node[string]
+	first int
+}
+
+// phraseMatcher sits between the scan and its visitor. It holds back the tokens that may
+// be part of a phrase until the phrase is complete, in which case a single censored token
+// spanning the whole phrase is passed on, or until no phrase can be completed anymore
+type phraseMatcher struct {
+	pd      *ProfanityDetector
+	input   string
+	pending []*tokenBuffer
+	walks   []phraseWalk
+}
+
+func (pm *phraseMatcher) visit(tb *tokenBuffer, next func(tb *tokenBuffer)) {
+	if !pm.pd.phrases.isWord(tb.norm) {
+		pm.walks = pm.walks[:0]
+		pm.release(len(pm.pending), next)
+		next(tb)
+		return
+	}
+	word := string(tb.norm)
+	current := len(pm.pending)
+	held := newTokenBuffer()
+	held.copyFrom(tb)
+	pm.pending = append(pm.pending, held)
+	complete := -1
+	nextWalks := pm.walks[:0]
+	for _, w := range pm.walks {
+		if child, ok := w.n.children[word]; ok {
+			nextWalks = append(nextWalks, phraseWalk{n: child, first: w.first})
+			if child.isEnd && (complete < 0 || w.first < complete) {
+				complete = w.first
+			}
+		}
+	}
+	if child, ok := pm.pd.phrases.phrases.root.children[word]; ok {
+		nextWalks = append(nextWalks, phraseWalk{n: child, first: current})
+	}
+	pm.walks = nextWalks
+	if complete >= 0 {
+		pm.release(complete, next)
+		pm.emitPhrase(next)
+		return
+	}
+	oldest := len(pm.pending)
+	for _, w := range pm.walks {
+		oldest = min(oldest, w.first)
+	}
+	pm.release(oldest, next)
+}
+
+// release passes the first n pending tokens on
+func (pm *phraseMatcher) release(n int, next func(tb *tokenBuffer)) {
+	for _, tb := range pm.pending[:n] {
+		next(tb)
+		tb.close()
+	}
+	pm.pending = pm.pending[:copy(pm.pending, pm.pending[n:])]
+	for i := range pm.walks {
+		pm.walks[i].first -= n
+	}
+}
+
+// emitPhrase passes all pending tokens on as a single censored phrase
+func (pm *phraseMatcher) emitPhrase(next func(tb *tokenBuffer)) {
+	first, last := pm.pending[0], pm.pending[len(pm.pending)-1]
+	words := make([]string, len(pm.pending))
+	for i, tb := range pm.pending {
+		words[i] = string(tb.norm)
+		tb.close()
+	}
+	pm.pending = pm.pending[:0]
+	pm.walks = pm.walks[:0]
+	phrase := newTokenBuffer()
+	defer phrase.close()
+	for i, r := range pm.input[first.start:last.end] {
+		phrase.push(r, pm.pd.normalize(r), first.start+i)
+	}
+	phrase.offsets = append(phrase.offsets, last.end)
+	phrase.start, phrase.end = first.start, last.end
+	phrase.matches = append(phrase.matches, runeSpan{start: 0, end: len(phrase.norm), word: strings.Join(words, " ")})
+	phrase.censored = true
+	next(phrase)
+}
+
+// close passes the remaining pending tokens on
+func (pm *phraseMatcher) close(next func(tb *tokenBuffer)) {
+	pm.walks = pm.walks[:0]
+	pm.release(len(pm.pending), next)
+}
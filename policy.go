@@ -0,0 +1,287 @@
+package pchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Policy is a named detector configuration which can be loaded from JSON:
+//
+//	{
+//	  "name": "forum",
+//	  "profanities": {"defaults": true, "add": ["^noob$"], "remove": ["gay"]},
+//	  "falsePositives": {"defaults": true},
+//	  "falseNegatives": {"defaults": true},
+//	  "severityThreshold": 1,
+//	  "replacement": {"strategy": "keepFirst", "text": "#"}
+//	}
+type Policy struct {
+	Name           string     `json:"name"`
+	Profanities    Dictionary `json:"profanities"`
+	FalsePositives Dictionary `json:"falsePositives"`
+	FalseNegatives Dictionary `json:"falseNegatives"`
+	// CharacterReplacements maps single runes to single runes, nil means DefaultCharacterReplacements
+	CharacterReplacements map[string]string `json:"characterReplacements,omitempty"`
+	// Severities override DefaultSeverities
+	Severities        map[string]float64 `json:"severities,omitempty"`
+	SeverityThreshold float64            `json:"severityThreshold,omitempty"`
+	// Categories override DefaultCategories
+	Categories  map[string]Category `json:"categories,omitempty"`
+	Replacement Replacement         `json:"replacement"`
+	// Entities recognizes URLs, emails, mentions, hashtags and code spans with DefaultEntityPolicies
+	Entities     bool `json:"entities,omitempty"`
+	Fuzzy        bool `json:"fuzzy,omitempty"`
+	Phonetic     bool `json:"phonetic,omitempty"`
+	ContextRules bool `json:"contextRules,omitempty"`
+	Clinical     bool `json:"clinical,omitempty"`
+}
+
+// Dictionary is a source of dictionary entries
+type Dictionary struct {
+	Defaults bool     `json:"defaults"`         // start with the default list
+	Add      []string `json:"add,omitempty"`    // entries added to the list
+	Remove   []string `json:"remove,omitempty"` // entries removed from the list
+}
+
+// Replacement describes how censored tokens are replaced
+type Replacement struct {
+	// Strategy is one of
+	//	"mask"       every rune of the token is replaced by Text, "*" by default
+	//	"keepFirst"  like mask but the first rune is kept
+	//	"fixed"      the token is replaced by Text
+	Strategy string `json:"strategy,omitempty"`
+	Text     string `json:"text,omitempty"`
+}
+
+// DefaultPolicies are always available by name
+var DefaultPolicies = []Policy{
+	{
+		Name:           "default",
+		Profanities:    Dictionary{Defaults: true},
+		FalsePositives: Dictionary{Defaults: true},
+		FalseNegatives: Dictionary{Defaults: true},
+	},
+	{
+		Name:           "kids",
+		Profanities:    Dictionary{Defaults: true, Add: []string{"^damn", "^hell$", "^idiot", "^stupid$", "^sucks?$"}},
+		FalsePositives: Dictionary{Defaults: true},
+		FalseNegatives: Dictionary{Defaults: true},
+	},
+	{
+		Name:              "gaming",
+		Profanities:       Dictionary{Defaults: true},
+		FalsePositives:    Dictionary{Defaults: true},
+		FalseNegatives:    Dictionary{Defaults: true},
+		SeverityThreshold: 2,
+		Replacement:       Replacement{Strategy: "keepFirst"},
+	},
+	{
+		Name:           "clinical",
+		Profanities:    Dictionary{Defaults: true},
+		FalsePositives: Dictionary{Defaults: true},
+		FalseNegatives: Dictionary{Defaults: true},
+		ContextRules:   true,
+		Clinical:       true,
+	},
+}
+
+var (
+	policiesMu sync.RWMutex
+	policies   = make(map[string]Policy)
+)
+
+func init() {
+	for _, p := range DefaultPolicies {
+		policies[p.Name] = p
+	}
+}
+
+// RegisterPolicy makes the policy available by its name, replacing any policy with the same name
+func RegisterPolicy(p Policy) error {
+	return registerPolicies([]Policy{p})
+}
+
+// registerPolicies registers all the policies or none of them if any is invalid
+func registerPolicies(ps []Policy) error {
+	for _, p := range ps {
+		if p.Name == "" {
+			return fmt.Errorf("pchecker: policy without a name")
+		}
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	for _, p := range ps {
+		policies[p.Name] = p
+	}
+	return nil
+}
+
+// LookupPolicy returns the registered policy with the given name
+func LookupPolicy(name string) (Policy, bool) {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	p, ok := policies[name]
+	return p, ok
+}
+
+// LoadPolicies registers the policies of a JSON array read from r. If any policy is invalid
+// none of them is registered
+func LoadPolicies(r io.Reader) error {
+	var loaded []Policy
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&loaded); err != nil {
+		return fmt.Errorf("pchecker: decoding policies: %w", err)
+	}
+	return registerPolicies(loaded)
+}
+
+// NewDetectorFromPolicy builds a detector from the registered policy with the given name
+func NewDetectorFromPolicy(name string) (*ProfanityDetector, error) {
+	p, ok := LookupPolicy(name)
+	if !ok {
+		return nil, fmt.Errorf("pchecker: unknown policy %q", name)
+	}
+	return p.NewDetector()
+}
+
+// NewDetector builds a detector configured by the policy
+func (p Policy) NewDetector() (*ProfanityDetector, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	pd := NewProfanityDetector().
+		WithFalsePositives(p.FalsePositives.entries(DefaultFalsePositives)).
+		WithFalseNegatives(p.FalseNegatives.entries(DefaultFalseNegatives)).
+		WithProfanities(p.Profanities.entries(DefaultProfanities)).
+		WithSeverityThreshold(p.SeverityThreshold).
+		WithReplacement(p.Replacement.Func())
+	if p.CharacterReplacements == nil {
+		pd.WithDefaultCharacterReplacements()
+	} else {
+		replacements := make(map[rune]rune, len(p.CharacterReplacements))
+		for from, to := range p.CharacterReplacements {
+			r, _ := utf8.DecodeRuneInString(from)
+			replacements[r], _ = utf8.DecodeRuneInString(to)
+		}
+		pd.WithCharacterReplacements(replacements)
+	}
+	severities := maps.Clone(DefaultSeverities)
+	maps.Copy(severities, p.Severities)
+	pd.WithSeverities(severities)
+	categories := maps.Clone(DefaultCategories)
+	maps.Copy(categories, p.Categories)
+	pd.WithCategories(categories)
+	if p.Entities {
+		pd.WithDefaultEntityPolicies()
+	}
+	if p.Fuzzy {
+		pd.WithDefaultFuzzy()
+	}
+	if p.Phonetic {
+		pd.WithDefaultPhonetic()
+	}
+	if p.ContextRules {
+		pd.WithDefaultContextRules()
+	}
+	if p.Clinical {
+		pd.WithClinicalProfile()
+	}
+	return pd, nil
+}
+
+func (p Policy) validate() error {
+	for from, to := range p.CharacterReplacements {
+		if utf8.RuneCountInString(from) != 1 || utf8.RuneCountInString(to) != 1 {
+			return fmt.Errorf("pchecker: policy %q: character replacement %q -> %q must map a single rune to a single rune", p.Name, from, to)
+		}
+	}
+	switch p.Replacement.Strategy {
+	case "", "mask", "keepFirst":
+		if utf8.RuneCountInString(p.Replacement.Text) > 1 {
+			return fmt.Errorf("pchecker: policy %q: %q replacement text must be a single rune", p.Name, p.Replacement.Strategy)
+		}
+	case "fixed":
+	default:
+		return fmt.Errorf("pchecker: policy %q: unknown replacement strategy %q", p.Name, p.Replacement.Strategy)
+	}
+	for _, d := range []Dictionary{p.Profanities, p.FalsePositives, p.FalseNegatives} {
+		for _, entry := range d.Add {
+			if err := ValidateEntry(entry); err != nil {
+				return fmt.Errorf("pchecker: policy %q: %w", p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (d Dictionary) entries(defaults map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(defaults)+len(d.Add))
+	if d.Defaults {
+		maps.Copy(result, defaults)
+	}
+	for _, entry := range d.Add {
+		result[entry] = true
+	}
+	for _, entry := range d.Remove {
+		delete(result, entry)
+	}
+	return result
+}
+
+// Func returns the replacement function of the strategy
+func (r Replacement) Func() ReplacementFunc {
+	switch r.Strategy {
+	case "fixed":
+		return FixedReplacement(r.Text)
+	case "keepFirst":
+		return KeepFirstReplacement(r.mask())
+	default:
+		return MaskReplacement(r.mask())
+	}
+}
+
+func (r Replacement) mask() rune {
+	if r.Text == "" {
+		return '*'
+	}
+	mask, _ := utf8.DecodeRuneInString(r.Text)
+	return mask
+}
+
+// MaskReplacement replaces every rune of the match by mask
+func MaskReplacement(mask rune) ReplacementFunc {
+	return func(match []rune) string {
+		return strings.Repeat(string(mask), len(match))
+	}
+}
+
+// KeepFirstReplacement keeps the first rune of the match and replaces the others by mask
+func KeepFirstReplacement(mask rune) ReplacementFunc {
+	return func(match []rune) string {
+		if len(match) == 0 {
+			return ""
+		}
+		return string(match[0]) + strings.Repeat(string(mask), len(match)-1)
+	}
+}
+
+// FixedReplacement replaces every match by text
+func FixedReplacement(text string) ReplacementFunc {
+	return func([]rune) string {
+		return text
+	}
+}
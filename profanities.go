@@ -106,4 +106,8 @@ var DefaultProfanities = map[string]bool{
 	"vagina":      true,
 	"wank":        true,
 	"whore":       true,
+
+	// phrases, matched across token boundaries
+	"kill yourself": true,
+	"suck my":       true,
 }
@@ -1,6 +1,9 @@
 package pchecker
 
 import (
+	"slices"
+	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
@@ -13,10 +16,26 @@ import (
 // ProfanityDetector contains the dictionaries as well as the configuration
 // for determining how profanity detection is handled
 type ProfanityDetector struct {
-	profanities           *SafeTrie[rune]
-	falsePositives        *SafeTrie[rune]
-	falseNegatives        *SafeTrie[rune]
+	profanities           *SafeTrieMap[rune, EntryInfo]
+	falsePositives        *SafeTrieMap[rune, EntryInfo]
+	falseNegatives        *SafeTrieMap[rune, EntryInfo]
 	characterReplacements map[rune]rune
+	entityPolicies        map[EntityKind]EntityPolicy
+	segmentWords          *SafeTrie[rune]
+	segmentation          bool
+	anchored              bool // some entries do not match anywhere
+	patternSeparators     bool // some pattern entries match separators
+	fuzzy                 FuzzyDistance
+	phonetic              *phoneticIndex
+	phrases               *phraseIndex
+	contextRules          *contextRules
+	severities            map[string]float64
+	severityThreshold     float64
+	categories            map[string]Category
+	replacement           ReplacementFunc
+	normalizer            Normalizer
+	observer              Observer
+	limits                Limits
 }
 
 func NewProfanityDetector() *ProfanityDetector {
@@ -29,40 +48,46 @@ func NewDefaultProfanityDetector() *ProfanityDetector {
 		WithDefaultFalsePositives().
 		WithDefaultFalseNegatives().
 		WithDefaultProfanities().
-		WithDefaultCharacterReplacements()
+		WithDefaultCharacterReplacements().
+		WithDefaultSeverities().
+		WithDefaultCategories()
 }
 
 func (pd *ProfanityDetector) WithProfanities(profanities map[string]bool) *ProfanityDetector {
-	pd.profanities = getSafeTrie(profanities).WithComparator(unicode.ToLower)
+	pd.profanities = pd.getDictionary(profanities)
+	pd.phrases = pd.getPhrases(profanities)
+	pd.indexPhonetic()
 	return pd
 }
 
 func (pd *ProfanityDetector) Profanities() *SafeTrie[rune] {
-	return pd.profanities
+	return pd.profanities.trie()
 }
 
 func (pd *ProfanityDetector) WithDefaultProfanities() *ProfanityDetector {
-	pd.profanities = getSafeTrie(DefaultProfanities).WithComparator(unicode.ToLower)
+	pd.profanities = pd.getDictionary(DefaultProfanities)
+	pd.phrases = pd.getPhrases(DefaultProfanities)
+	pd.indexPhonetic()
 	return pd
 }
 
 func (pd *ProfanityDetector) WithFalsePositives(falsePositives map[string]bool) *ProfanityDetector {
-	pd.falsePositives = getSafeTrie(falsePositives).WithComparator(unicode.ToLower)
+	pd.falsePositives = pd.getDictionary(falsePositives)
 	return pd
 }
 
 func (pd *ProfanityDetector) WithDefaultFalsePositives() *ProfanityDetector {
-	pd.falsePositives = getSafeTrie(DefaultFalsePositives).WithComparator(unicode.ToLower)
+	pd.falsePositives = pd.getDictionary(DefaultFalsePositives)
 	return pd
 }
 
 func (pd *ProfanityDetector) WithFalseNegatives(falseNegatives map[string]bool) *ProfanityDetector {
-	pd.falseNegatives = getSafeTrie(falseNegatives).WithComparator(unicode.ToLower)
+	pd.falseNegatives = pd.getDictionary(falseNegatives)
 	return pd
 }
 
 func (pd *ProfanityDetector) WithDefaultFalseNegatives() *ProfanityDetector {
-	pd.falseNegatives = getSafeTrie(DefaultFalseNegatives).WithComparator(unicode.ToLower)
+	pd.falseNegatives = pd.getDictionary(DefaultFalseNegatives)
 	return pd
 }
 
@@ -76,43 +101,287 @@ func (pd *ProfanityDetector) WithDefaultCharacterReplacements() *ProfanityDetect
 	return pd
 }
 
+// WithReplacement sets the replacement used when Censor is called with a nil ReplacementFunc
+func (pd *ProfanityDetector) WithReplacement(f ReplacementFunc) *ProfanityDetector {
+	pd.replacement = f
+	return pd
+}
+
+// replacementOr returns f, or the configured replacement if f is nil
+func (pd *ProfanityDetector) replacementOr(f ReplacementFunc) ReplacementFunc {
+	switch {
+	case f != nil:
+		return f
+	case pd.replacement != nil:
+		return pd.replacement
+	default:
+		return MaskReplacement('*')
+	}
+}
+
+// Censor replaces the censored tokens of the input by f, a nil f uses the configured replacement
 func (pd *ProfanityDetector) Censor(input string, f ReplacementFunc) string {
-	tb := newTokenBuffer(utf8.RuneCountInString(input), f)
+	result, _ := pd.censor(input, pd.replacementOr(f), nil)
+	return result
+}
+
+// censor replaces the censored tokens of the input by f within the limits of lim
+func (pd *ProfanityDetector) censor(input string, f ReplacementFunc, lim *scanLimits) (string, error) {
+	var result strings.Builder
+	last := -1
+	err := pd.scanLimited(input, lim, func(tb *tokenBuffer) {
+		if !tb.censored {
+			return
+		}
+		if last < 0 {
+			result.Grow(len(input))
+			last = 0
+		}
+		result.WriteString(input[last:tb.start])
+		result.WriteString(f(tb.buff))
+		last = tb.end
+	})
+	switch {
+	case err != nil:
+		return "", err
+	case last < 0:
+		return input, nil
+	}
+	result.WriteString(input[last:])
+	return result.String(), nil
+}
+
+type scanMode uint8
+
+const (
+	scanEntities  scanMode = 1 << iota // recognize entities at token boundaries
+	scanWordBreak                      // split lower-cased tokens with the dictionary word break
+)
+
+// scan walks the input token by token and calls visit for every token, the token buffer
+// is only valid during the call
+func (pd *ProfanityDetector) scan(input string, visit func(tb *tokenBuffer)) {
+	_ = pd.scanLimited(input, nil, visit)
+}
+
+// scanLimited is scan stopping with an error when lim is exceeded or cancelled
+func (pd *ProfanityDetector) scanLimited(input string, lim *scanLimits, visit func(tb *tokenBuffer)) error {
+	var mode scanMode
+	if pd.entityPolicies != nil {
+		mode |= scanEntities
+	}
+	// the matchers take the next visitor as an argument to keep visit on the stack
+	if pd.observer != nil {
+		so := scanObserver{pd: pd, ctx: lim.context(), start: time.Now(), event: ScanEvent{Bytes: len(input)}}
+		next := visit
+		defer so.close()
+		visit = func(tb *tokenBuffer) { so.visit(tb, next) }
+	}
+	if pd.contextRules != nil {
+		cm := contextMatcher{pd: pd}
+		next := visit
+		defer cm.close(next)
+		visit = func(tb *tokenBuffer) { cm.visit(tb, next) }
+	}
+	if pd.phrases != nil {
+		pm := phraseMatcher{pd: pd, input: input}
+		next := visit
+		defer pm.close(next)
+		visit = func(tb *tokenBuffer) { pm.visit(tb, next) }
+	}
+	pd.scanTokens(input, 0, mode, lim, visit)
+	return lim.error()
+}
+
+// scanTokens scans the input located at the byte offset base of the original input
+func (pd *ProfanityDetector) scanTokens(input string, base int, mode scanMode, lim *scanLimits, visit func(tb *tokenBuffer)) {
+	tb := newTokenBuffer()
 	defer tb.close()
-	activeNodes := make([]*node[rune], 0, 32)
-	for _, r := range input {
-		if (r != '@' && r != '_' && unicode.IsPunct(r)) || unicode.IsSpace(r) {
-			tb.flush(pd.falsePositives, pd.falseNegatives)
-			tb.result.WriteRune(r)
-			activeNodes = activeNodes[:0] // reset active nodes
-			tb.buff = tb.buff[:0]         // reset buffer
+	boundary, truncated := true, false
+	for i := 0; i < len(input); {
+		if lim.stopped() {
+			return
+		}
+		if mode&scanEntities != 0 && boundary {
+			if end := pd.scanEntity(input, i, base, lim, visit); end > i {
+				i = end
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(input[i:])
+		if isSeparator(r) && !pd.continuesWord(tb, r) {
+			pd.flush(tb, base+i, mode, visit)
+			boundary, truncated = true, false
+			i += size
 			continue
 		}
-		tb.buff = append(tb.buff, r)
-		normRune := pd.getCharReplacement(r)
-		nextActive := activeNodes[:0]
-		for _, n := range activeNodes {
-			if child, ok := n.children[unicode.ToLower(normRune)]; ok {
-				nextActive = append(nextActive, child)
-				if child.isEnd {
-					tb.badToken = true
-				}
+		if truncated {
+			i += size
+			continue
+		}
+		if len(tb.buff) == 0 {
+			tb.start = base + i
+		}
+		boundary = false
+		if lim.tokenFull(len(tb.buff)) {
+			switch lim.TokenAction {
+			case LimitReject:
+				lim.err = ErrTokenTooLong
+				return
+			case LimitCensor:
+				// only the end of the token is needed to censor it
+				tb.oversized = true
+				i += size
+				continue
+			default:
+				pd.flush(tb, base+i, mode, visit)
+				truncated = true
+				i += size
+				continue
 			}
 		}
-		if child, ok := pd.profanities.root.children[unicode.ToLower(normRune)]; ok {
-			nextActive = append(nextActive, child)
-			if child.isEnd {
-				tb.badToken = true
+		tb.push(r, pd.normalize(r), base+i)
+		i += size
+		// segments are walked on flush, but the walks of the token decide on the separators
+		if !pd.segmentation || pd.patternSeparators {
+			pd.step(tb, len(tb.norm)-1)
+		}
+	}
+	pd.flush(tb, base+len(input), mode, visit)
+}
+
+// activeNode is a trie walk that started at the rune index start of the token
+type activeNode struct {
+	n     *node[rune]
+	start int
+}
+
+// step advances the profanity, false positive and false negative walks by the rune of
+// the normalized token at pos and records the entries ending at it
+func (pd *ProfanityDetector) step(tb *tokenBuffer, pos int) {
+	tb.active, tb.matches = walk(pd.profanities.trie(), tb.active, tb.matches, tb.norm, pos)
+	tb.fpActive, tb.falsePositives = walk(pd.falsePositives.trie(), tb.fpActive, tb.falsePositives, tb.norm, pos)
+	tb.fnActive, tb.falseNegatives = walk(pd.falseNegatives.trie(), tb.fnActive, tb.falseNegatives, tb.norm, pos)
+}
+
+// walk advances the active walks of the trie by norm[pos], the token is normalized
+// by the comparator of the trie already. It appends the words ending at norm[pos] to matches
+func walk(t *SafeTrie[rune], activeNodes []activeNode, matches []runeSpan, norm []rune, pos int) ([]activeNode, []runeSpan) {
+	if t == nil {
+		return activeNodes, matches
+	}
+	r := norm[pos]
+	nextActive := activeNodes[:0]
+	advance := func(child *node[rune], start int) {
+		nextActive = append(nextActive, activeNode{n: child, start: start})
+		if child.isEnd {
+			matches = append(matches, runeSpan{start: start, end: pos + 1, entry: child})
+		}
+	}
+	for _, a := range activeNodes {
+		if child, ok := a.n.children[r]; ok {
+			advance(child, a.start)
+		}
+		if a.n.wildcard != nil {
+			advance(a.n.wildcard, a.start)
+		}
+	}
+	if child, ok := t.root.children[r]; ok {
+		advance(child, pos)
+	}
+	if t.root.wildcard != nil {
+		advance(t.root.wildcard, pos)
+	}
+	return nextActive, matches
+}
+
+// flush finishes the buffered token which ends at the byte offset end
+func (pd *ProfanityDetector) flush(tb *tokenBuffer, end int, mode scanMode, visit func(tb *tokenBuffer)) {
+	if len(tb.buff) == 0 {
+		return
+	}
+	tb.end = end
+	tb.offsets = append(tb.offsets, end)
+	if tb.oversized {
+		tb.censored = true
+		visit(tb)
+	} else if pd.segmentation {
+		pd.flushSegments(tb, mode&scanWordBreak != 0, visit)
+	} else {
+		pd.judge(tb)
+		visit(tb)
+	}
+	tb.reset()
+}
+
+// judge decides whether the token has to be censored: any false negative or any profanity
+// that is not fully covered by a false positive found in the token censors it
+func (pd *ProfanityDetector) judge(tb *tokenBuffer) {
+	if pd.fuzzy != nil {
+		pd.fuzzyMatches(tb)
+	}
+	if pd.phonetic != nil {
+		pd.phoneticMatch(tb)
+	}
+	pd.applyMatchModes(tb)
+	tb.censored = len(tb.falseNegatives) > 0
+	for _, m := range tb.matches {
+		if tb.censored {
+			return
+		}
+		tb.censored = !m.coveredBy(tb.falsePositives) && pd.isSevere(tb, m)
+	}
+}
+
+// censoringMatches calls visit for the matches responsible for censoring the judged token,
+// approximate matches only count when nothing matched exactly
+func (pd *ProfanityDetector) censoringMatches(tb *tokenBuffer, visit func(m runeSpan)) {
+	censoring := func(m runeSpan) bool {
+		return !m.coveredBy(tb.falsePositives) && pd.isSevere(tb, m)
+	}
+	exact := slices.ContainsFunc(tb.matches, func(m runeSpan) bool {
+		return m.distance == 0 && !m.phonetic && censoring(m)
+	})
+	for _, m := range tb.matches {
+		if censoring(m) && (!exact || (m.distance == 0 && !m.phonetic)) {
+			visit(m)
+		}
+	}
+	for _, m := range tb.falseNegatives {
+		visit(m)
+	}
+}
+
+// isSeparator reports whether r ends a token, '@' and '_' are part of words
+func isSeparator(r rune) bool {
+	return (r != '@' && r != '_' && unicode.IsPunct(r)) || unicode.IsSpace(r)
+}
+
+// continuesWord reports whether the separator r is part of the buffered token, because a
+// profanity or false negative pattern walking the token can match it
+func (pd *ProfanityDetector) continuesWord(tb *tokenBuffer, r rune) bool {
+	if !pd.patternSeparators || len(tb.buff) == 0 || unicode.IsSpace(r) {
+		return false
+	}
+	for _, active := range [...][]activeNode{tb.active, tb.fnActive} {
+		for _, a := range active {
+			if a.n.wildcard != nil || slices.Contains(a.n.separators, r) {
+				return true
 			}
 		}
-		activeNodes = nextActive
 	}
-	tb.flush(pd.falsePositives, pd.falseNegatives)
-	return tb.String()
+	return false
+}
+
+// normalize returns the rune used for the trie lookups, the character replacements are
+// looked up on the normalized rune so "ｓｈ１ｔ" is read like "sh1t" with width folding
+func (pd *ProfanityDetector) normalize(r rune) rune {
+	fold := pd.fold()
+	return fold(pd.getCharReplacement(fold(r)))
 }
 
 func (pd *ProfanityDetector) getCharReplacement(original rune) rune {
-	if replacement, found := pd.characterReplacements[unicode.ToLower(original)]; found {
+	if replacement, found := pd.characterReplacements[original]; found {
 		return replacement
 	}
 	return original
@@ -0,0 +1,6 @@
+//go:build race
+
+package pchecker
+
+// raceEnabled reports whether the race detector is on, which makes sync.Pool drop buffers at random
+const raceEnabled = true
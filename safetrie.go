@@ -2,6 +2,8 @@ package pchecker
 
 import (
 	"fmt"
+	"iter"
+	"slices"
 	"sync"
 )
 
@@ -16,12 +18,28 @@ type SafeTrie[K comparable] struct {
 	lock       sync.RWMutex
 	comparator func(K) K
 	strFunc    func([]K) string
+	size       int
+	merged     func(into, from *node[K]) // called when the word ending at from is merged into into
 }
 
 // node represents a node in the Trie
 type node[K comparable] struct {
 	children map[K]*node[K]
-	isEnd    bool // Marks the end of a word
+	wildcard *node[K] // child matching any key, only created by pattern entries
+	// separators are the keys of the children which continue a word past a separator,
+	// only set by pattern entries
+	separators []K
+	isEnd      bool // Marks the end of a word
+}
+
+// child returns the child of the key, creating it if it does not exist
+func (n *node[K]) child(key K) *node[K] {
+	if _, exists := n.children[key]; !exists {
+		n.children[key] = &node[K]{
+			children: make(map[K]*node[K]),
+		}
+	}
+	return n.children[key]
 }
 
 func NewSafeTrie[K comparable](length int) *SafeTrie[K] {
@@ -37,26 +55,93 @@ func (t *SafeTrie[K]) WithStrFunc(f func([]K) string) *SafeTrie[K] {
 	return t
 }
 
+// WithComparator sets the normalization applied to every key on insert and lookup.
+// The keys already in the trie are normalized too, words becoming equal are merged
 func (t *SafeTrie[K]) WithComparator(f func(K) K) *SafeTrie[K] {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	t.comparator = f
+	if f != nil {
+		t.rekey(t.root)
+	}
 	return t
 }
 
+// rekey normalizes the keys below n. The caller must hold the lock
+func (t *SafeTrie[K]) rekey(n *node[K]) {
+	if n == nil {
+		return
+	}
+	children := n.children
+	n.children = make(map[K]*node[K], len(children))
+	for key, child := range children {
+		t.rekey(child)
+		t.mergeChild(n, t.compare(key), child)
+	}
+	t.rekey(n.wildcard)
+}
+
+// mergeChild adds child to the children of parent, merging it with an existing child of the key
+func (t *SafeTrie[K]) mergeChild(parent *node[K], key K, child *node[K]) {
+	if existing, ok := parent.children[key]; ok {
+		t.merge(existing, child)
+	} else {
+		parent.children[key] = child
+	}
+}
+
+// merge moves the words below from to into
+func (t *SafeTrie[K]) merge(into, from *node[K]) {
+	if from.isEnd {
+		if into.isEnd {
+			t.size--
+		}
+		into.isEnd = true
+		if t.merged != nil {
+			t.merged(into, from)
+		}
+	}
+	for _, key := range from.separators {
+		if !slices.Contains(into.separators, key) {
+			into.separators = append(into.separators, key)
+		}
+	}
+	for key, child := range from.children {
+		t.mergeChild(into, key, child)
+	}
+	switch {
+	case from.wildcard == nil:
+	case into.wildcard == nil:
+		into.wildcard = from.wildcard
+	default:
+		t.merge(into.wildcard, from.wildcard)
+	}
+}
+
 // Insert adds a word to the Trie
 func (t *SafeTrie[K]) Insert(arr []K) {
+	t.insert(arr)
+}
+
+// insert adds a word to the Trie and returns the node it ends at
+func (t *SafeTrie[K]) insert(arr []K) *node[K] {
 	t.lock.Lock()
 	defer t.lock.Unlock()
+	return t.add(arr)
+}
+
+// add is insert for a caller holding the lock
+func (t *SafeTrie[K]) add(arr []K) *node[K] {
 	n := t.root
 	for _, key := range arr {
 		// If the symbol does not exist, create a new node
-		if _, exists := n.children[key]; !exists {
-			n.children[key] = &node[K]{
-				children: make(map[K]*node[K]),
-			}
-		}
-		n = n.children[key]
+		n = n.child(t.compare(key))
+	}
+	if !n.isEnd {
+		n.isEnd = true
+		t.size++
 	}
-	n.isEnd = true
+	return n
 }
 
 // Exists checks if a word exists in the Trie
@@ -95,9 +180,20 @@ func (t *SafeTrie[K]) StartsWith(arr []K) (bool, bool) {
 }
 
 func (t *SafeTrie[K]) IsPrefixInTrie(arr []K) bool {
+	_, ok := t.prefix(arr)
+	return ok
+}
+
+// prefix walks the trie along arr as far as possible. It returns the number of keys walked
+// and whether the walk ended in a leaf, i.e. whether a word of the trie is a prefix of arr
+func (t *SafeTrie[K]) prefix(arr []K) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 	n := t.root
+	walked := 0
 	for _, ch := range arr {
 		if t.comparator != nil {
 			ch = t.comparator(ch)
@@ -106,24 +202,252 @@ func (t *SafeTrie[K]) IsPrefixInTrie(arr []K) bool {
 			break
 		}
 		n = n.children[ch]
+		walked++
 	}
-	return n.children == nil || len(n.children) == 0
+	return walked, n.children == nil || len(n.children) == 0
 }
 
+// PrintAll prints every word of the trie, using strFunc if it is set
 func (t *SafeTrie[K]) PrintAll() {
-	if t.strFunc == nil {
-		panic("string function is not set")
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	t.words(func(word []K) {
+		if t.strFunc != nil {
+			fmt.Println(t.strFunc(word))
+		} else {
+			fmt.Println(word)
+		}
+	})
+}
+
+// words calls f for every word of the trie, the slice is only valid during the call.
+// Wildcard edges of pattern entries are not followed. The caller must hold the lock
+func (t *SafeTrie[K]) words(f func(word []K)) {
+	t.walkFrom(t.root, nil, func(word []K) bool {
+		f(word)
+		return true
+	})
+}
+
+// Len returns the number of words in the trie
+func (t *SafeTrie[K]) Len() int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.size
+}
+
+// Walk calls f for every word of the trie in no particular order until f returns false.
+// The slice is only valid during the call and the trie must not be modified by f
+func (t *SafeTrie[K]) Walk(f func(word []K) bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	t.walkFrom(t.root, nil, f)
+}
+
+// walkFrom calls f for every word below n until f returns false, which it reports.
+// The caller must hold the lock
+func (t *SafeTrie[K]) walkFrom(n *node[K], prefix []K, f func(word []K) bool) bool {
+	return t.walkNodes(n, prefix, func(word []K, _ *node[K]) bool {
+		return f(word)
+	})
+}
+
+// walkNodes is walkFrom also passing the node every word ends at
+func (t *SafeTrie[K]) walkNodes(n *node[K], prefix []K, f func(word []K, end *node[K]) bool) bool {
+	if n.isEnd && !f(prefix, n) {
+		return false
+	}
+	for key, child := range n.children {
+		if !t.walkNodes(child, append(prefix, key), f) {
+			return false
+		}
+	}
+	return true
+}
+
+// All returns an iterator over the words of the trie in no particular order.
+// The words are copied, so the trie may be modified during the iteration
+func (t *SafeTrie[K]) All() iter.Seq[[]K] {
+	return t.KeysWithPrefix(nil)
+}
+
+// KeysWithPrefix returns an iterator over the words of the trie starting with prefix
+func (t *SafeTrie[K]) KeysWithPrefix(prefix []K) iter.Seq[[]K] {
+	return func(yield func([]K) bool) {
+		var words [][]K
+		t.lock.RLock()
+		if n := t.find(prefix); n != nil {
+			t.walkFrom(n, slices.Clone(t.normalize(prefix)), func(word []K) bool {
+				words = append(words, slices.Clone(word))
+				return true
+			})
+		}
+		t.lock.RUnlock()
+		for _, word := range words {
+			if !yield(word) {
+				return
+			}
+		}
 	}
+}
+
+// LongestPrefixOf returns the length of the longest word of the trie that is a prefix of arr
+func (t *SafeTrie[K]) LongestPrefixOf(arr []K) (int, bool) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
-	var dfs func(n *node[K], prefix []K)
-	dfs = func(n *node[K], prefix []K) {
+	longest, found := 0, t.root.isEnd
+	n := t.root
+	for i, ch := range arr {
+		if n = n.children[t.compare(ch)]; n == nil {
+			break
+		}
 		if n.isEnd {
-			fmt.Println(t.strFunc(prefix))
+			longest, found = i+1, true
+		}
+	}
+	return longest, found
+}
+
+// Delete removes a word from the trie and prunes the nodes only it used, it reports whether the word existed
+func (t *SafeTrie[K]) Delete(arr []K) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, ok := t.delete(arr)
+	return ok
+}
+
+// delete removes a word and returns the node it ended at. The caller must hold the lock
+func (t *SafeTrie[K]) delete(arr []K) (*node[K], bool) {
+	path := make([]*node[K], 0, len(arr)+1)
+	n := t.root
+	path = append(path, n)
+	for _, ch := range arr {
+		if n = n.children[t.compare(ch)]; n == nil {
+			return nil, false
+		}
+		path = append(path, n)
+	}
+	if !n.isEnd {
+		return nil, false
+	}
+	end := n
+	n.isEnd = false
+	t.size--
+	for i := len(arr); i > 0; i-- {
+		n = path[i]
+		if n.isEnd || len(n.children) > 0 || n.wildcard != nil {
+			break
+		}
+		delete(path[i-1].children, t.compare(arr[i-1]))
+	}
+	return end, true
+}
+
+// find returns the node arr leads to, or nil. The caller must hold the lock
+func (t *SafeTrie[K]) find(arr []K) *node[K] {
+	n := t.root
+	for _, ch := range arr {
+		if n = n.children[t.compare(ch)]; n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+func (t *SafeTrie[K]) compare(key K) K {
+	if t.comparator != nil {
+		return t.comparator(key)
+	}
+	return key
+}
+
+func (t *SafeTrie[K]) normalize(arr []K) []K {
+	if t.comparator == nil {
+		return arr
+	}
+	result := make([]K, len(arr))
+	for i, key := range arr {
+		result[i] = t.comparator(key)
+	}
+	return result
+}
+
+// TrieMatch is an occurrence of a word of the trie in a sequence
+type TrieMatch struct {
+	Start int // index of the first key of the word in the sequence
+	End   int // index just after the last key of the word in the sequence
+}
+
+// Matcher finds the words of a trie in sequences, following the wildcard edges of pattern entries
+type Matcher[K comparable] struct {
+	t *SafeTrie[K]
+}
+
+func NewMatcher[K comparable](t *SafeTrie[K]) *Matcher[K] {
+	return &Matcher[K]{t: t}
+}
+
+// FindAll returns an iterator over every occurrence, overlapping ones included,
+// ordered by start and then by end
+func (m *Matcher[K]) FindAll(seq []K) iter.Seq[TrieMatch] {
+	return func(yield func(TrieMatch) bool) {
+		for _, match := range m.find(seq, false) {
+			if !yield(match) {
+				return
+			}
+		}
+	}
+}
+
+// FindLongest returns an iterator over the leftmost longest occurrences which do not overlap
+func (m *Matcher[K]) FindLongest(seq []K) iter.Seq[TrieMatch] {
+	return func(yield func(TrieMatch) bool) {
+		for _, match := range m.find(seq, true) {
+			if !yield(match) {
+				return
+			}
+		}
+	}
+}
+
+// Contains reports whether any word of the trie occurs in seq
+func (m *Matcher[K]) Contains(seq []K) bool {
+	for range m.FindAll(seq) {
+		return true
+	}
+	return false
+}
+
+func (m *Matcher[K]) find(seq []K, longest bool) []TrieMatch {
+	t := m.t
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	var result []TrieMatch
+	var current, next []*node[K]
+	for start := 0; start < len(seq); start++ {
+		current = append(current[:0], t.root)
+		first := len(result)
+		for end := start; end < len(seq) && len(current) > 0; end++ {
+			key := t.compare(seq[end])
+			next = next[:0]
+			ended := false
+			for _, n := range current {
+				for _, child := range [...]*node[K]{n.children[key], n.wildcard} {
+					if child != nil {
+						next = append(next, child)
+						ended = ended || child.isEnd
+					}
+				}
+			}
+			if ended {
+				result = append(result, TrieMatch{Start: start, End: end + 1})
+			}
+			current, next = next, current
 		}
-		for r, child := range n.children {
-			dfs(child, append(prefix, r))
+		if longest && len(result) > first {
+			result = append(result[:first], result[len(result)-1])
+			start = result[first].End - 1
 		}
 	}
-	dfs(t.root, nil)
+	return result
 }
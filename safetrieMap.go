@@ -0,0 +1,188 @@
+package pchecker
+
+import (
+	"iter"
+	"slices"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// SafeTrieMap is a concurrently safe prefix tree carrying a value for every word.
+// Words inserted through the embedded SafeTrie carry the zero value
+type SafeTrieMap[K comparable, V any] struct {
+	SafeTrie[K]
+	values map[*node[K]]V
+	order  map[*node[K]]int // insertion order of the values
+	next   int
+	join   func(first, later V) V
+}
+
+func NewSafeTrieMap[K comparable, V any](length int) *SafeTrieMap[K, V] {
+	t := &SafeTrieMap[K, V]{
+		SafeTrie: SafeTrie[K]{
+			root: &node[K]{
+				children: make(map[K]*node[K], length),
+			},
+		},
+		values: make(map[*node[K]]V, length),
+		order:  make(map[*node[K]]int, length),
+	}
+	// words becoming equal by normalization join their values in insertion order,
+	// whatever order the nodes are merged in
+	t.merged = func(into, from *node[K]) {
+		v, ok := t.values[from]
+		if !ok {
+			return
+		}
+		order := t.order[from]
+		delete(t.values, from)
+		delete(t.order, from)
+		existing, exists := t.values[into]
+		switch {
+		case !exists:
+			t.values[into], t.order[into] = v, order
+		case order < t.order[into]:
+			t.values[into], t.order[into] = t.joined(v, existing), order
+		default:
+			t.values[into] = t.joined(existing, v)
+		}
+	}
+	return t
+}
+
+// WithJoin sets how the values of words becoming equal by normalization are combined,
+// by default the value put first is kept
+func (t *SafeTrieMap[K, V]) WithJoin(f func(first, later V) V) *SafeTrieMap[K, V] {
+	t.join = f
+	return t
+}
+
+func (t *SafeTrieMap[K, V]) joined(first, later V) V {
+	if t.join == nil {
+		return first
+	}
+	return t.join(first, later)
+}
+
+// set assigns the value of the word ending at n, joining it with an existing value if join is set.
+// The caller must hold the lock
+func (t *SafeTrieMap[K, V]) set(n *node[K], value V, join bool) {
+	if existing, exists := t.values[n]; exists {
+		if join {
+			value = t.joined(existing, value)
+		}
+		t.values[n] = value
+		return
+	}
+	t.values[n], t.order[n] = value, t.next
+	t.next++
+}
+
+// WithComparator sets the normalization applied to every key on insert and lookup,
+// see SafeTrie.WithComparator
+func (t *SafeTrieMap[K, V]) WithComparator(f func(K) K) *SafeTrieMap[K, V] {
+	t.SafeTrie.WithComparator(f)
+	return t
+}
+
+// trie returns the embedded SafeTrie, or nil for a nil map
+func (t *SafeTrieMap[K, V]) trie() *SafeTrie[K] {
+	if t == nil {
+		return nil
+	}
+	return &t.SafeTrie
+}
+
+// Put adds a word with its value, replacing the value of an existing word
+func (t *SafeTrieMap[K, V]) Put(arr []K, value V) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.set(t.add(arr), value, false)
+}
+
+// Get returns the value of a word
+func (t *SafeTrieMap[K, V]) Get(arr []K) (V, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	n := t.find(arr)
+	if n == nil || !n.isEnd {
+		var zero V
+		return zero, false
+	}
+	return t.values[n], true
+}
+
+// value returns the value of the word ending at n, it is nil-safe.
+// The trie must not be modified concurrently
+func (t *SafeTrieMap[K, V]) value(n *node[K]) (V, bool) {
+	if t == nil || n == nil {
+		var zero V
+		return zero, false
+	}
+	v, ok := t.values[n]
+	return v, ok
+}
+
+// Delete removes a word and its value, it reports whether the word existed
+func (t *SafeTrieMap[K, V]) Delete(arr []K) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	n, ok := t.delete(arr)
+	delete(t.values, n)
+	delete(t.order, n)
+	return ok
+}
+
+// All returns an iterator over the words and their values in no particular order
+func (t *SafeTrieMap[K, V]) All() iter.Seq2[[]K, V] {
+	return t.WithPrefix(nil)
+}
+
+// WithPrefix returns an iterator over the words starting with prefix and their values.
+// The words are copied, so the trie may be modified during the iteration
+func (t *SafeTrieMap[K, V]) WithPrefix(prefix []K) iter.Seq2[[]K, V] {
+	return func(yield func([]K, V) bool) {
+		type entry struct {
+			word  []K
+			value V
+		}
+		var entries []entry
+		t.lock.RLock()
+		if n := t.find(prefix); n != nil {
+			t.walkNodes(n, slices.Clone(t.normalize(prefix)), func(word []K, n *node[K]) bool {
+				entries = append(entries, entry{word: slices.Clone(word), value: t.values[n]})
+				return true
+			})
+		}
+		t.lock.RUnlock()
+		for _, e := range entries {
+			if !yield(e.word, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// LongestPrefixOf returns the length and the value of the longest word that is a prefix of arr
+func (t *SafeTrieMap[K, V]) LongestPrefixOf(arr []K) (int, V, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	var value V
+	longest, found := 0, t.root.isEnd
+	if found {
+		value = t.values[t.root]
+	}
+	n := t.root
+	for i, ch := range arr {
+		if n = n.children[t.compare(ch)]; n == nil {
+			break
+		}
+		if n.isEnd {
+			longest, value, found = i+1, t.values[n], true
+		}
+	}
+	return longest, value, found
+}
@@ -0,0 +1,142 @@
+package pchecker
+
+import (
+	"math"
+	"unicode"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// DefaultSeverity is the weight of entries without a configured severity
+const DefaultSeverity = 1.0
+
+// DefaultSeverities weight slurs and threats above mild or anatomical words
+var DefaultSeverities = map[string]float64{
+	"kill yourself": 3,
+	"coon":          3,
+	"fag":           3,
+	"nigga":         3,
+	"nigger":        3,
+	"niggu":         3,
+	"retard":        2.5,
+	"rape":          3,
+	"rapist":        3,
+	"pedophile":     3,
+	"cunt":          2.5,
+	"fuck":          2,
+	"whore":         2,
+	"slut":          2,
+	"bitch":         2,
+	"shit":          1.5,
+	"abortion":      0.25,
+	"babies":        0.25,
+	"breast":        0.5,
+	"nipple":        0.5,
+	"naked":         0.5,
+	"nude":          0.5,
+	"gay":           0.5,
+	"butt":          0.5,
+	"bum":           0.5,
+	"crap":          0.5,
+	"poop":          0.25,
+	"jerk":          0.5,
+	"sex":           0.5,
+}
+
+// Score rates how toxic a message is, messages with higher values are worse
+type Score struct {
+	Value      float64 // combined score in [0, 1]
+	Matches    int     // number of censored tokens
+	Severity   float64 // sum of the severities of the censored tokens
+	Density    float64 // share of the tokens that are censored
+	Evasion    float64 // share of the censored tokens disguised by leetspeak, masking, homoglyphs, misspellings or sound-alikes
+	Repetition float64 // share of the censored tokens repeating an entry seen before in the message
+}
+
+// WithSeverities sets the weights of entries used by Score, the missing ones weigh DefaultSeverity
+func (pd *ProfanityDetector) WithSeverities(severities map[string]float64) *ProfanityDetector {
+	pd.severities = severities
+	pd.annotate()
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultSeverities() *ProfanityDetector {
+	return pd.WithSeverities(DefaultSeverities)
+}
+
+// Score rates the input. The value grows with the severity of the censored tokens and
+// saturates towards 1, it is then weighted by density, evasion and repetition:
+//
+//	Value = (1 - e^(-Severity/2)) * (0.6 + 0.2*Density + 0.1*Evasion + 0.1*Repetition)
+func (pd *ProfanityDetector) Score(input string) Score {
+	var result Score
+	var tokens, evasive, repeated int
+	seen := make(map[string]bool)
+	pd.scan(input, func(tb *tokenBuffer) {
+		tokens++
+		if !tb.censored {
+			return
+		}
+		result.Matches++
+		severity, worst, evaded := -1.0, "", false
+		pd.censoringMatches(tb, func(m runeSpan) {
+			if match := pd.match(tb, m); match.Severity > severity {
+				severity, worst = match.Severity, match.Entry
+			}
+			evaded = evaded || m.distance > 0 || m.phonetic || pd.isDisguised(tb, m)
+		})
+		result.Severity += max(severity, 0)
+		if evaded {
+			evasive++
+		}
+		if seen[worst] {
+			repeated++
+		}
+		seen[worst] = true
+	})
+	if result.Matches == 0 {
+		return result
+	}
+	n := float64(result.Matches)
+	result.Density = n / float64(tokens)
+	result.Evasion = float64(evasive) / n
+	result.Repetition = float64(repeated) / n
+	result.Value = (1 - math.Exp(-result.Severity/2)) *
+		(0.6 + 0.2*result.Density + 0.1*result.Evasion + 0.1*result.Repetition)
+	return result
+}
+
+func (pd *ProfanityDetector) severity(entry string) float64 {
+	if severity, ok := pd.severities[entry]; ok {
+		return severity
+	}
+	return DefaultSeverity
+}
+
+// WithSeverityThreshold stops censoring of entries weighing less than threshold, false negatives are always censored
+func (pd *ProfanityDetector) WithSeverityThreshold(threshold float64) *ProfanityDetector {
+	pd.severityThreshold = threshold
+	return pd
+}
+
+func (pd *ProfanityDetector) isSevere(tb *tokenBuffer, m runeSpan) bool {
+	return pd.severityThreshold <= 0 || pd.info(tb, m).Severity >= pd.severityThreshold
+}
+
+// isDisguised reports whether the matched runes use character replacements,
+// masking symbols or non-ASCII look-alikes of the entry
+func (pd *ProfanityDetector) isDisguised(tb *tokenBuffer, m runeSpan) bool {
+	for i := m.start; i < m.end; i++ {
+		r := tb.buff[i]
+		switch {
+		case pd.isReplaced(unicode.ToLower(r)),
+			unicode.IsPunct(r) || unicode.IsSymbol(r),
+			r > unicode.MaxASCII && unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package pchecker
+
+import (
+	"unicode"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// WithSegmentation splits every token into sub-words before looking for profanities, so only
+// the offending sub-words get censored ("BigAssTruck" becomes "Big***Truck"). Tokens are split
+// on lower to upper case changes and between letters and digits that are not character replacements.
+// Lower-cased hashtags are split with a dictionary based word break which knows the given words
+// as well as the profanities and false positives, when hashtags are recognized with EntityScan
+func (pd *ProfanityDetector) WithSegmentation(words map[string]bool) *ProfanityDetector {
+	pd.segmentation = true
+	pd.segmentWords = getSafeTrie(words, pd.fold())
+	return pd
+}
+
+// flushSegments judges the buffered token segment by segment
+func (pd *ProfanityDetector) flushSegments(tb *tokenBuffer, wordBreak bool, visit func(tb *tokenBuffer)) {
+	if tb.segment == nil {
+		tb.segment = &tokenBuffer{}
+	}
+	seg := tb.segment
+	tb.bounds = pd.segmentBounds(tb.buff, tb.norm, wordBreak, tb.bounds[:0])
+	for k := 0; k+1 < len(tb.bounds); k++ {
+		from, to := tb.bounds[k], tb.bounds[k+1]
+		seg.start, seg.end = tb.offsets[from], tb.offsets[to]
+		seg.reset()
+		seg.buff, seg.norm, seg.offsets = tb.buff[from:to], tb.norm[from:to], tb.offsets[from:to+1]
+		for i := range seg.norm {
+			pd.step(seg, i)
+		}
+		pd.judge(seg)
+		visit(seg)
+	}
+}
+
+// segmentBounds appends the rune offsets of the segments of token to dst, including 0 and len(token),
+// norm is the normalized token
+func (pd *ProfanityDetector) segmentBounds(token, norm []rune, wordBreak bool, dst []int) []int {
+	dst = append(dst, 0)
+	upper := unicode.IsUpper(token[0])
+	for i := 1; i < len(token); i++ {
+		prev, r := token[i-1], token[i]
+		upper = upper || unicode.IsUpper(r)
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(r),
+			unicode.IsLetter(prev) && unicode.IsDigit(r) && !pd.isReplaced(r),
+			unicode.IsDigit(prev) && !pd.isReplaced(prev) && unicode.IsLetter(r):
+			dst = append(dst, i)
+		}
+	}
+	if wordBreak && len(dst) == 1 && !upper {
+		dst = pd.wordBreak(norm, dst)
+	}
+	return append(dst, len(token))
+}
+
+func (pd *ProfanityDetector) isReplaced(r rune) bool {
+	_, ok := pd.characterReplacements[pd.fold()(r)]
+	return ok
+}
+
+// wordBreak splits the normalized token into known words so that as few runes as possible stay
+// outside of them, preferring longer words. Unknown runes are kept together as one segment
+func (pd *ProfanityDetector) wordBreak(token []rune, dst []int) []int {
+	type state struct {
+		unknown, words, from int
+		word                 bool
+	}
+	best := make([]state, len(token)+1)
+	for i := range best[1:] {
+		best[i+1].unknown = len(token) + 1
+	}
+	relax := func(to int, s state) {
+		if b := best[to]; s.unknown < b.unknown || s.unknown == b.unknown && s.words < b.words {
+			best[to] = s
+		}
+	}
+	dictionaries := [...]*SafeTrie[rune]{pd.profanities.trie(), pd.falsePositives.trie(), pd.segmentWords}
+	for i := range token {
+		relax(i+1, state{unknown: best[i].unknown + 1, words: best[i].words, from: i})
+		for _, dict := range dictionaries {
+			if dict == nil {
+				continue
+			}
+			n := dict.root
+			for j := i; j < len(token); j++ {
+				child, ok := n.children[token[j]]
+				if !ok {
+					break
+				}
+				if n = child; n.isEnd {
+					relax(j+1, state{unknown: best[i].unknown, words: best[i].words + 1, from: i, word: true})
+				}
+			}
+		}
+	}
+	// collect the bounds of the known words from the end, unknown runes in between stay together
+	var cuts []int
+	for to := len(token); to > 0; to = best[to].from {
+		if best[to].word {
+			cuts = append(cuts, to, best[to].from)
+		}
+	}
+	for i := len(cuts) - 1; i >= 0; i-- {
+		if c := cuts[i]; c > 0 && c < len(token) && c != dst[len(dst)-1] {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,118 @@
+package pchecker
+
+import (
+	"context"
+	"log/slog"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// SlogHandler censors the message and the string attributes of log records before passing
+// them to the wrapped handler. Attributes implementing slog.LogValuer are resolved first
+type SlogHandler struct {
+	next slog.Handler
+	pd   *ProfanityDetector
+	f    ReplacementFunc
+	keys map[string]bool
+	// grouped is set once a group opened with WithGroup is selected by its key
+	grouped bool
+}
+
+// NewSlogHandler wraps next, a nil f uses the configured replacement of the detector
+func (pd *ProfanityDetector) NewSlogHandler(next slog.Handler, f ReplacementFunc) *SlogHandler {
+	return &SlogHandler{
+		next: next,
+		pd:   pd,
+		f:    f,
+	}
+}
+
+// WithKeys restricts censoring to the attributes with the given keys, a group key selects
+// every attribute of the group. The message is always censored. It must be called before
+// the handler is used
+func (h *SlogHandler) WithKeys(keys ...string) *SlogHandler {
+	if h.keys == nil {
+		h.keys = make(map[string]bool, len(keys))
+	}
+	for _, key := range keys {
+		h.keys[key] = true
+	}
+	return h
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	censored := slog.NewRecord(r.Time, r.Level, h.pd.Censor(r.Message, h.f), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		censored.AddAttrs(h.censorAttr(a, h.selected()))
+		return true
+	})
+	return h.next.Handle(ctx, censored)
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	censored := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		censored[i] = h.censorAttr(a, h.selected())
+	}
+	clone := *h
+	clone.next = h.next.WithAttrs(censored)
+	return &clone
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.grouped = h.grouped || h.keys[name]
+	return &clone
+}
+
+// selected reports whether every attribute is censored, without keys or inside a selected group
+func (h *SlogHandler) selected() bool {
+	return h.keys == nil || h.grouped
+}
+
+// censorAttr censors the attribute if it is selected, by its own key or by the key of an enclosing group
+func (h *SlogHandler) censorAttr(a slog.Attr, selected bool) slog.Attr {
+	selected = selected || h.keys[a.Key]
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if selected {
+			a.Value = slog.StringValue(h.pd.Censor(a.Value.String(), h.f))
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		censored := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			censored[i] = h.censorAttr(ga, selected)
+		}
+		a.Value = slog.GroupValue(censored...)
+	}
+	return a
+}
+
+// UserText is user provided text which is censored when it is logged
+type UserText struct {
+	pd   *ProfanityDetector
+	text string
+	f    ReplacementFunc
+}
+
+// UserText wraps text for logging, a nil f uses the configured replacement of the detector:
+//
+//	logger.Info("message received", "text", pd.UserText(msg, nil))
+func (pd *ProfanityDetector) UserText(text string, f ReplacementFunc) UserText {
+	return UserText{pd: pd, text: text, f: f}
+}
+
+// LogValue implements slog.LogValuer, the text is only censored if the record is handled
+func (t UserText) LogValue() slog.Value {
+	return slog.StringValue(t.pd.Censor(t.text, t.f))
+}
@@ -1,7 +1,6 @@
 package pchecker
 
 import (
-	"strings"
 	"sync"
 )
 
@@ -10,42 +9,94 @@ import (
  * @date    9/17/2025
  **/
 
-type tokenBuffer struct {
-	buffPool sync.Pool
-	buff     []rune
-	badToken bool
-	result   strings.Builder
-	f        ReplacementFunc
+var tokenBufferPool = sync.Pool{New: func() any {
+	return &tokenBuffer{
+		buff:    make([]rune, 0, 16),
+		norm:    make([]rune, 0, 16),
+		offsets: make([]int, 0, 17),
+		active:  make([]activeNode, 0, 32),
+	}
+}}
+
+// runeSpan is a half-open range of rune indices of a token matched by a dictionary entry
+type runeSpan struct {
+	start, end int
+	entry      *node[rune] // trie node the entry ends at
+	distance   int         // edit distance of a fuzzy match
+	word       string      // entry of a fuzzy or phonetic match, exact matches are read from the token
+	phonetic   bool
 }
 
-func newTokenBuffer(resultLen int, f ReplacementFunc) *tokenBuffer {
-	result := &tokenBuffer{
-		f: f,
-	}
-	result.result.Grow(resultLen)
-	result.buffPool = sync.Pool{New: func() any { return make([]rune, 0, 16) }}
-	result.buff = result.buffPool.Get().([]rune)[:0]
-	return result
-}
-
-func (tb *tokenBuffer) flush(falsePositives, falseNegatives *SafeTrie[rune]) {
-	if len(tb.buff) > 0 {
-		if tb.badToken && (!falsePositives.IsPrefixInTrie(tb.buff) || falseNegatives.IsPrefixInTrie(tb.buff)) {
-			tb.result.WriteString(tb.f(tb.buff))
-		} else {
-			for _, r := range tb.buff {
-				tb.result.WriteRune(r)
-			}
+// coveredBy reports whether any of the spans fully covers s
+func (s runeSpan) coveredBy(spans []runeSpan) bool {
+	for _, c := range spans {
+		if c.start <= s.start && s.end <= c.end {
+			return true
 		}
-		tb.buff = tb.buff[:0]
-		tb.badToken = false
 	}
+	return false
+}
+
+// tokenBuffer holds the token being scanned together with everything known about it
+type tokenBuffer struct {
+	buff           []rune     // runes of the token as they appear in the input
+	norm           []rune     // runes after character replacement and lower casing
+	offsets        []int      // byte offset of every rune in the input, followed by the end of the token
+	matches        []runeSpan // profanities found in norm
+	falsePositives []runeSpan // false positives found in norm
+	falseNegatives []runeSpan // false negatives found in norm
+	start, end     int        // byte span of the token in the input
+	censored       bool
+	oversized      bool // exceeds the maximum token length and is censored without matching
+	// active trie walks
+	active   []activeNode
+	fpActive []activeNode
+	fnActive []activeNode
+	// scratch space of the segmentation
+	bounds  []int
+	segment *tokenBuffer
+	// scratch space of the phonetic matching
+	key []byte
+}
+
+func newTokenBuffer() *tokenBuffer {
+	return tokenBufferPool.Get().(*tokenBuffer)
+}
+
+// push appends the rune r located at the byte offset offset, norm is its normalized form
+func (tb *tokenBuffer) push(r, norm rune, offset int) {
+	tb.buff = append(tb.buff, r)
+	tb.norm = append(tb.norm, norm)
+	tb.offsets = append(tb.offsets, offset)
+}
+
+// copyFrom makes tb a copy of the judged token src
+func (tb *tokenBuffer) copyFrom(src *tokenBuffer) {
+	tb.buff = append(tb.buff[:0], src.buff...)
+	tb.norm = append(tb.norm[:0], src.norm...)
+	tb.offsets = append(tb.offsets[:0], src.offsets...)
+	tb.matches = append(tb.matches[:0], src.matches...)
+	tb.falsePositives = append(tb.falsePositives[:0], src.falsePositives...)
+	tb.falseNegatives = append(tb.falseNegatives[:0], src.falseNegatives...)
+	tb.start, tb.end = src.start, src.end
+	tb.censored = src.censored
 }
 
-func (tb *tokenBuffer) String() string {
-	return tb.result.String()
+func (tb *tokenBuffer) reset() {
+	tb.buff = tb.buff[:0]
+	tb.norm = tb.norm[:0]
+	tb.offsets = tb.offsets[:0]
+	tb.matches = tb.matches[:0]
+	tb.falsePositives = tb.falsePositives[:0]
+	tb.falseNegatives = tb.falseNegatives[:0]
+	tb.active = tb.active[:0]
+	tb.fpActive = tb.fpActive[:0]
+	tb.fnActive = tb.fnActive[:0]
+	tb.censored = false
+	tb.oversized = false
 }
 
 func (tb *tokenBuffer) close() {
-	tb.buffPool.Put(tb.buff[:0])
+	tb.reset()
+	tokenBufferPool.Put(tb)
 }
@@ -0,0 +1,167 @@
+package pchecker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/**
+ * @author  papajuan
+ * @date    10/19/2026
+ **/
+
+// Category groups dictionary entries by the kind of harm they do
+type Category string
+
+const (
+	CategoryProfanity Category = "profanity" // entries without a configured category
+	CategoryInsult    Category = "insult"
+	CategorySexual    Category = "sexual"
+	CategorySlur      Category = "slur"
+	CategoryViolence  Category = "violence"
+)
+
+// DefaultCategories categorizes the entries of DefaultProfanities
+var DefaultCategories = map[string]Category{
+	"abbo": CategorySlur, "coon": CategorySlur, "dyke": CategorySlur, "fag": CategorySlur, "nazi": CategorySlur,
+	"niger": CategorySlur, "nigga": CategorySlur, "nigger": CategorySlur, "niggu": CategorySlur, "retard": CategorySlur,
+	"kill yourself": CategoryViolence, "rape": CategoryViolence, "rapist": CategoryViolence, "abuse": CategoryViolence,
+	"abusive": CategoryViolence,
+	"asshole": CategoryInsult, "bastard": CategoryInsult, "biatch": CategoryInsult, "bitch": CategoryInsult,
+	"btch": CategoryInsult, "cunt": CategoryInsult, "douchebag": CategoryInsult, "dumbass": CategoryInsult,
+	"jerk": CategoryInsult, "prick": CategoryInsult, "slut": CategoryInsult, "twat": CategoryInsult, "whore": CategoryInsult,
+	"anal": CategorySexual, "blowjob": CategorySexual, "boner": CategorySexual, "clitoris": CategorySexual,
+	"cum": CategorySexual, "dildo": CategorySexual, "fellate": CategorySexual, "fellatio": CategorySexual,
+	"horny": CategorySexual, "incest": CategorySexual, "jizz": CategorySexual, "masturbat": CategorySexual,
+	"naked": CategorySexual, "nude": CategorySexual, "penis": CategorySexual, "porn": CategorySexual,
+	"prostitut": CategorySexual, "rimjob": CategorySexual, "sex": CategorySexual, "spunk": CategorySexual,
+	"suck my": CategorySexual, "suckmy": CategorySexual, "vagina": CategorySexual, "wank": CategorySexual,
+}
+
+// Violation is a censored token of a rejected input
+type Violation struct {
+	Token      string     // token as it appears in the input
+	Start      int        // byte offset of the token in the input
+	End        int        // byte offset just after the token in the input
+	Matches    []Match    // matches responsible for censoring the token
+	Categories []Category // distinct categories of the matches
+}
+
+// ProfanityError is returned by Validate for rejected input
+type ProfanityError struct {
+	Violations []Violation
+}
+
+func (e *ProfanityError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("pchecker: input contains profanity")
+	for i, v := range e.Violations {
+		if i == 3 {
+			fmt.Fprintf(&sb, " and %d more", len(e.Violations)-i)
+			break
+		}
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, " %q at [%d:%d]", v.Token, v.Start, v.End)
+	}
+	return sb.String()
+}
+
+// Categories returns the distinct categories of all the violations
+func (e *ProfanityError) Categories() []Category {
+	var result []Category
+	for _, v := range e.Violations {
+		for _, c := range v.Categories {
+			result = appendCategory(result, c)
+		}
+	}
+	return result
+}
+
+// WithCategories sets the categories of entries, the missing ones are CategoryProfanity
+func (pd *ProfanityDetector) WithCategories(categories map[string]Category) *ProfanityDetector {
+	pd.categories = categories
+	pd.annotate()
+	return pd
+}
+
+func (pd *ProfanityDetector) WithDefaultCategories() *ProfanityDetector {
+	return pd.WithCategories(DefaultCategories)
+}
+
+func (pd *ProfanityDetector) category(entry string) Category {
+	if category, ok := pd.categories[entry]; ok {
+		return category
+	}
+	return CategoryProfanity
+}
+
+// Validate returns a *ProfanityError describing every token Censor would censor, or nil
+func (pd *ProfanityDetector) Validate(input string) error {
+	var violations []Violation
+	pd.scan(input, func(tb *tokenBuffer) {
+		if !tb.censored {
+			return
+		}
+		v := Violation{Token: input[tb.start:tb.end], Start: tb.start, End: tb.end}
+		pd.censoringMatches(tb, func(m runeSpan) {
+			match := pd.match(tb, m)
+			v.Matches = append(v.Matches, match)
+			v.Categories = appendCategory(v.Categories, match.Category)
+		})
+		violations = append(violations, v)
+	})
+	if violations == nil {
+		return nil
+	}
+	return &ProfanityError{Violations: violations}
+}
+
+// IsClean reports whether Censor would leave the input unchanged
+func (pd *ProfanityDetector) IsClean(input string) bool {
+	clean := true
+	pd.scan(input, func(tb *tokenBuffer) {
+		clean = clean && !tb.censored
+	})
+	return clean
+}
+
+// ValidField reports whether a struct field is clean. Strings, pointers to strings and slices,
+// arrays and maps of them are checked, other kinds are considered clean. It is meant for
+// custom validators of go-playground/validator:
+//
+//	validate.RegisterValidation("clean", func(fl validator.FieldLevel) bool {
+//		return pd.ValidField(fl.Field())
+//	})
+func (pd *ProfanityDetector) ValidField(field reflect.Value) bool {
+	switch field.Kind() {
+	case reflect.String:
+		return pd.IsClean(field.String())
+	case reflect.Pointer, reflect.Interface:
+		return field.IsNil() || pd.ValidField(field.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := range field.Len() {
+			if !pd.ValidField(field.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Map:
+		for it := field.MapRange(); it.Next(); {
+			if !pd.ValidField(it.Key()) || !pd.ValidField(it.Value()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func appendCategory(dst []Category, c Category) []Category {
+	for _, existing := range dst {
+		if existing == c {
+			return dst
+		}
+	}
+	return append(dst, c)
+}